@@ -0,0 +1,111 @@
+package event
+
+import (
+	"context"
+	"log"
+	"slices"
+	"sync"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// Handler handles a published order event
+type Handler func(ctx context.Context, event entity.OrderEvent) error
+
+// subscription is a registered handler and the event types it receives
+type subscription struct {
+	name       string
+	handler    Handler
+	async      bool
+	eventTypes []string
+}
+
+// receives reports whether the subscription wants events of the given type
+func (s *subscription) receives(eventType string) bool {
+	return len(s.eventTypes) == 0 || slices.Contains(s.eventTypes, eventType)
+}
+
+// Dispatcher delivers order events to in-process subscribers after the change that raised
+// them was committed. A failing or panicking subscriber is logged and never affects the
+// publisher or the other subscribers.
+type Dispatcher struct {
+	mu            sync.RWMutex
+	subscriptions []*subscription
+	pending       sync.WaitGroup
+}
+
+// NewDispatcher creates a new Dispatcher
+func NewDispatcher() *Dispatcher {
+	return &Dispatcher{}
+}
+
+// Subscribe registers a handler run synchronously by Publish for the given event types,
+// or for all events when none are given. Synchronous handlers should be fast.
+func (d *Dispatcher) Subscribe(name string, handler Handler, eventTypes ...string) {
+	d.subscribe(&subscription{name: name, handler: handler, eventTypes: eventTypes})
+}
+
+// SubscribeAsync registers a handler run in its own goroutine for the given event types,
+// or for all events when none are given
+func (d *Dispatcher) SubscribeAsync(name string, handler Handler, eventTypes ...string) {
+	d.subscribe(&subscription{name: name, handler: handler, async: true, eventTypes: eventTypes})
+}
+
+// Unsubscribe removes the handlers registered under name, such as the handler of a
+// client that disconnected
+func (d *Dispatcher) Unsubscribe(name string) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions = slices.DeleteFunc(d.subscriptions, func(s *subscription) bool { return s.name == name })
+}
+
+// subscribe adds a subscription
+func (d *Dispatcher) subscribe(s *subscription) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.subscriptions = append(d.subscriptions, s)
+}
+
+// Publish delivers events in order to the subscribers. Synchronous subscribers have run
+// when Publish returns; asynchronous ones get a context that is not cancelled with ctx.
+func (d *Dispatcher) Publish(ctx context.Context, events ...entity.OrderEvent) {
+	d.mu.RLock()
+	subscriptions := slices.Clone(d.subscriptions)
+	d.mu.RUnlock()
+
+	for _, event := range events {
+		for _, s := range subscriptions {
+			if !s.receives(event.Type) {
+				continue
+			}
+			if !s.async {
+				d.deliver(ctx, s, event)
+				continue
+			}
+
+			d.pending.Add(1)
+			go func(s *subscription, event entity.OrderEvent) {
+				defer d.pending.Done()
+				d.deliver(context.WithoutCancel(ctx), s, event)
+			}(s, event)
+		}
+	}
+}
+
+// Wait blocks until all asynchronous deliveries started so far have finished
+func (d *Dispatcher) Wait() {
+	d.pending.Wait()
+}
+
+// deliver runs a handler, logging its error or panic
+func (d *Dispatcher) deliver(ctx context.Context, s *subscription, event entity.OrderEvent) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Event subscriber %s panicked on %s %s: %v", s.name, event.Type, event.ID, r)
+		}
+	}()
+
+	if err := s.handler(ctx, event); err != nil {
+		log.Printf("Event subscriber %s failed on %s %s: %v", s.name, event.Type, event.ID, err)
+	}
+}
@@ -0,0 +1,226 @@
+package event
+
+import (
+	"context"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// feedBatchSize is how many outbox events the feed reads at a time
+const feedBatchSize = 500
+
+// FeedEvent is an order event with its ID in the feed, the position of the event in the
+// outbox. IDs are the same on every replica and survive restarts, so clients can resume
+// after an event on any replica.
+type FeedEvent struct {
+	ID       string
+	Event    entity.OrderEvent
+	position entity.OutboxPosition
+}
+
+// Feed follows the order events of every replica through the outbox and fans them out to
+// live subscribers, such as streaming API clients. It polls the outbox, and right away when
+// this replica publishes events. Subscribers can resume after an event the outbox still holds.
+type Feed struct {
+	outboxRepo repository.OutboxRepository
+	// history is how many missed events a resuming subscriber may be sent
+	history int
+	wake    chan struct{}
+
+	mu sync.Mutex
+	// position is the last event delivered; started reports whether it has been read yet
+	position    entity.OutboxPosition
+	started     bool
+	subscribers map[*FeedSubscription]struct{}
+}
+
+// FeedSubscription receives the events of a feed selected by its filter until it is closed.
+// A subscriber that falls more than its buffer behind is dropped and its Done channel closed.
+type FeedSubscription struct {
+	events chan FeedEvent
+	done   chan struct{}
+	filter func(event *entity.OrderEvent) bool
+	// skipUntil is the last event the subscriber has already seen, when it resumed after
+	// an event this feed had not delivered yet
+	skipUntil entity.OutboxPosition
+	once      sync.Once
+}
+
+// NewFeed creates a Feed reading outboxRepo that resumes subscribers at most history events
+// behind. It polls right away when dispatcher publishes events of this replica.
+func NewFeed(outboxRepo repository.OutboxRepository, dispatcher *Dispatcher, history int) *Feed {
+	f := &Feed{
+		outboxRepo:  outboxRepo,
+		history:     history,
+		wake:        make(chan struct{}, 1),
+		subscribers: make(map[*FeedSubscription]struct{}),
+	}
+	dispatcher.Subscribe("feed", func(ctx context.Context, event entity.OrderEvent) error {
+		select {
+		case f.wake <- struct{}{}:
+		default:
+		}
+		return nil
+	})
+	return f
+}
+
+// Run polls the outbox every interval, and when woken by a published event, until ctx is
+// cancelled. The feed starts at the last event in the outbox.
+func (f *Feed) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		if err := f.poll(ctx); err != nil && ctx.Err() == nil {
+			log.Printf("Failed to read order events from the outbox: %v", err)
+		}
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-f.wake:
+		}
+	}
+}
+
+// poll delivers the outbox events after the feed position to the subscribers
+func (f *Feed) poll(ctx context.Context) error {
+	f.mu.Lock()
+	started, position := f.started, f.position
+	f.mu.Unlock()
+	if !started {
+		head, err := f.outboxRepo.Head(ctx)
+		if err != nil {
+			return err
+		}
+		f.mu.Lock()
+		f.position, f.started = head, true
+		f.mu.Unlock()
+		position = head
+	}
+
+	for {
+		entries, err := f.outboxRepo.FindAfter(ctx, position, feedBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, entry := range entries {
+			f.deliver(entry)
+			position = entry.Position
+		}
+		if len(entries) < feedBatchSize {
+			return nil
+		}
+	}
+}
+
+// deliver advances the feed to an event and sends it to the subscribers it is for
+func (f *Feed) deliver(entry entity.OutboxEntry) {
+	held := FeedEvent{ID: entry.Position.String(), Event: entry.Event, position: entry.Position}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.position = entry.Position
+	for sub := range f.subscribers {
+		if !held.position.After(sub.skipUntil) || !sub.filter(&held.Event) {
+			continue
+		}
+		select {
+		case sub.events <- held:
+		default:
+			delete(f.subscribers, sub)
+			sub.close()
+		}
+	}
+}
+
+// Subscribe registers a subscriber for the events selected by filter, or all events when
+// filter is nil. With a lastEventID it also returns the selected events the subscriber missed
+// since that one; resumed is false when they cannot be sent because the outbox no longer
+// holds that event or more than the feed history were missed.
+func (f *Feed) Subscribe(ctx context.Context, lastEventID string, buffer int, filter func(event *entity.OrderEvent) bool) (sub *FeedSubscription, missed []FeedEvent, resumed bool, err error) {
+	if filter == nil {
+		filter = func(*entity.OrderEvent) bool { return true }
+	}
+	sub = &FeedSubscription{
+		events: make(chan FeedEvent, buffer),
+		done:   make(chan struct{}),
+		filter: filter,
+	}
+	if lastEventID == "" {
+		f.mu.Lock()
+		f.subscribers[sub] = struct{}{}
+		f.mu.Unlock()
+		return sub, nil, true, nil
+	}
+
+	after, ok := entity.ParseOutboxPosition(lastEventID)
+	if ok {
+		if ok, err = f.outboxRepo.Exists(ctx, after); err != nil {
+			return nil, nil, false, err
+		}
+	}
+
+	// Events up to the feed position are read from the outbox, later ones are delivered
+	f.mu.Lock()
+	position, started := f.position, f.started
+	if ok && started && after.After(position) {
+		// The client saw events on a replica ahead of this one
+		sub.skipUntil = after
+	}
+	f.subscribers[sub] = struct{}{}
+	f.mu.Unlock()
+	if !ok || !started {
+		return sub, nil, false, nil
+	}
+	if !position.After(after) {
+		return sub, nil, true, nil
+	}
+
+	entries, err := f.outboxRepo.FindAfter(ctx, after, f.history+1)
+	if err != nil {
+		f.Unsubscribe(sub)
+		return nil, nil, false, err
+	}
+	count := 0
+	for _, entry := range entries {
+		if entry.Position.After(position) {
+			break
+		}
+		if count++; count > f.history {
+			return sub, nil, false, nil
+		}
+		if filter(&entry.Event) {
+			missed = append(missed, FeedEvent{ID: entry.Position.String(), Event: entry.Event, position: entry.Position})
+		}
+	}
+	return sub, missed, true, nil
+}
+
+// Unsubscribe removes a subscriber and closes its Done channel
+func (f *Feed) Unsubscribe(sub *FeedSubscription) {
+	f.mu.Lock()
+	delete(f.subscribers, sub)
+	f.mu.Unlock()
+	sub.close()
+}
+
+// Events returns the channel delivering the subscribed events
+func (s *FeedSubscription) Events() <-chan FeedEvent {
+	return s.events
+}
+
+// Done returns a channel closed when the subscriber was dropped for falling behind or unsubscribed
+func (s *FeedSubscription) Done() <-chan struct{} {
+	return s.done
+}
+
+// close closes the Done channel once
+func (s *FeedSubscription) close() {
+	s.once.Do(func() { close(s.done) })
+}
@@ -0,0 +1,227 @@
+package event
+
+import (
+	"context"
+	"slices"
+	"sync"
+	"testing"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// memoryOutbox holds outbox entries in position order, all of them settled. Methods the
+// feed does not use are left to the embedded interface and panic.
+type memoryOutbox struct {
+	repository.OutboxRepository
+	mu      sync.Mutex
+	entries []entity.OutboxEntry
+}
+
+// add appends an event for an order at the next position of transaction txID
+func (o *memoryOutbox) add(txID int64, orderID, status string) entity.OutboxPosition {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	position := entity.OutboxPosition{TxID: txID, Sequence: int64(len(o.entries) + 1)}
+	o.entries = append(o.entries, entity.OutboxEntry{
+		Position: position,
+		Event: entity.OrderEvent{ID: position.String(), Type: entity.OrderEventType.Updated, OrderID: orderID,
+			Order: entity.Order{ID: orderID, Status: status}},
+	})
+	return position
+}
+
+// prune drops the first n entries
+func (o *memoryOutbox) prune(n int) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	o.entries = o.entries[n:]
+}
+
+func (o *memoryOutbox) FindAfter(_ context.Context, after entity.OutboxPosition, limit int) ([]entity.OutboxEntry, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	var entries []entity.OutboxEntry
+	for _, entry := range o.entries {
+		if entry.Position.After(after) && len(entries) < limit {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+func (o *memoryOutbox) Head(context.Context) (entity.OutboxPosition, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	if len(o.entries) == 0 {
+		return entity.OutboxPosition{}, nil
+	}
+	return o.entries[len(o.entries)-1].Position, nil
+}
+
+func (o *memoryOutbox) Exists(_ context.Context, position entity.OutboxPosition) (bool, error) {
+	o.mu.Lock()
+	defer o.mu.Unlock()
+	return slices.ContainsFunc(o.entries, func(entry entity.OutboxEntry) bool { return entry.Position == position }), nil
+}
+
+// feedEventIDs returns the IDs of feed events in order
+func feedEventIDs(events []FeedEvent) []string {
+	ids := []string{}
+	for _, held := range events {
+		ids = append(ids, held.ID)
+	}
+	return ids
+}
+
+// receive returns the events queued for a subscriber
+func receive(sub *FeedSubscription) []FeedEvent {
+	var events []FeedEvent
+	for {
+		select {
+		case held := <-sub.Events():
+			events = append(events, held)
+		default:
+			return events
+		}
+	}
+}
+
+func TestFeedDeliversOutboxEventsAfterStart(t *testing.T) {
+	outbox := &memoryOutbox{}
+	outbox.add(10, "a", entity.OrderStatus.New)
+	feed := NewFeed(outbox, NewDispatcher(), 10)
+	if err := feed.poll(context.Background()); err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+
+	all, _, _, _ := feed.Subscribe(context.Background(), "", 10, nil)
+	processing, _, _, _ := feed.Subscribe(context.Background(), "", 10, func(event *entity.OrderEvent) bool {
+		return event.Order.Status == entity.OrderStatus.Processing
+	})
+	// Events of another replica's transactions arrive through the outbox like local ones
+	outbox.add(11, "a", entity.OrderStatus.Processing)
+	outbox.add(12, "b", entity.OrderStatus.New)
+	outbox.add(12, "b", entity.OrderStatus.Processing)
+	if err := feed.poll(context.Background()); err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+
+	if ids := feedEventIDs(receive(all)); !slices.Equal(ids, []string{"11-2", "12-3", "12-4"}) {
+		t.Errorf("subscriber received %v, want the events after the feed started", ids)
+	}
+	if ids := feedEventIDs(receive(processing)); !slices.Equal(ids, []string{"11-2", "12-4"}) {
+		t.Errorf("filtered subscriber received %v, want [11-2 12-4]", ids)
+	}
+}
+
+func TestFeedResume(t *testing.T) {
+	tests := []struct {
+		name        string
+		lastEventID string
+		// pruned is how many of the oldest events the outbox no longer holds
+		pruned      int
+		history     int
+		wantResumed bool
+		wantMissed  []string
+		// wantNext is what is delivered after the subscriber's events reach the outbox
+		wantNext []string
+	}{
+		{name: "missed events", lastEventID: "2-2", history: 10, wantResumed: true,
+			wantMissed: []string{"3-3", "4-4"}, wantNext: []string{"5-5"}},
+		{name: "nothing missed", lastEventID: "4-4", history: 10, wantResumed: true,
+			wantMissed: []string{}, wantNext: []string{"5-5"}},
+		{name: "as many missed as the history", lastEventID: "1-1", history: 3, wantResumed: true,
+			wantMissed: []string{"2-2", "3-3", "4-4"}, wantNext: []string{"5-5"}},
+		{name: "more missed than the history", lastEventID: "1-1", history: 2,
+			wantMissed: []string{}, wantNext: []string{"5-5"}},
+		{name: "pruned event", lastEventID: "1-1", pruned: 1, history: 10,
+			wantMissed: []string{}, wantNext: []string{"5-5"}},
+		{name: "unknown event", lastEventID: "3-9", history: 10,
+			wantMissed: []string{}, wantNext: []string{"5-5"}},
+		{name: "malformed ID", lastEventID: "6f1c2a9e-42x", history: 10,
+			wantMissed: []string{}, wantNext: []string{"5-5"}},
+		// The client saw 5-5 on a replica ahead of this one
+		{name: "ahead of the feed", lastEventID: "5-5", history: 10, wantResumed: true,
+			wantMissed: []string{}, wantNext: []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			outbox := &memoryOutbox{}
+			for txID := int64(1); txID <= 4; txID++ {
+				outbox.add(txID, "a", entity.OrderStatus.New)
+			}
+			feed := NewFeed(outbox, NewDispatcher(), tt.history)
+			if err := feed.poll(context.Background()); err != nil {
+				t.Fatalf("poll() error = %v", err)
+			}
+			outbox.add(5, "a", entity.OrderStatus.Processing)
+			outbox.prune(tt.pruned)
+
+			sub, missed, resumed, err := feed.Subscribe(context.Background(), tt.lastEventID, 10, nil)
+			if err != nil {
+				t.Fatalf("Subscribe() error = %v", err)
+			}
+			if resumed != tt.wantResumed || !slices.Equal(feedEventIDs(missed), tt.wantMissed) {
+				t.Errorf("Subscribe() = %v, resumed %v, want %v, resumed %v", feedEventIDs(missed), resumed, tt.wantMissed, tt.wantResumed)
+			}
+
+			if err := feed.poll(context.Background()); err != nil {
+				t.Fatalf("poll() error = %v", err)
+			}
+			if ids := feedEventIDs(receive(sub)); !slices.Equal(ids, tt.wantNext) {
+				t.Errorf("then received %v, want %v", ids, tt.wantNext)
+			}
+		})
+	}
+}
+
+func TestFeedResumeBeforeStart(t *testing.T) {
+	outbox := &memoryOutbox{}
+	outbox.add(1, "a", entity.OrderStatus.New)
+	feed := NewFeed(outbox, NewDispatcher(), 10)
+
+	// Until the feed has read its position it cannot tell what the subscriber missed
+	_, missed, resumed, err := feed.Subscribe(context.Background(), "1-1", 10, nil)
+	if err != nil || resumed || len(missed) != 0 {
+		t.Errorf("Subscribe() = %v, resumed %v, %v, want a reset", feedEventIDs(missed), resumed, err)
+	}
+}
+
+func TestFeedDropsSubscribersThatFallBehind(t *testing.T) {
+	outbox := &memoryOutbox{}
+	feed := NewFeed(outbox, NewDispatcher(), 10)
+	if err := feed.poll(context.Background()); err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+
+	sub, _, _, _ := feed.Subscribe(context.Background(), "", 2, nil)
+	for txID := int64(1); txID <= 3; txID++ {
+		outbox.add(txID, "a", entity.OrderStatus.New)
+	}
+	if err := feed.poll(context.Background()); err != nil {
+		t.Fatalf("poll() error = %v", err)
+	}
+
+	select {
+	case <-sub.Done():
+	default:
+		t.Fatal("a subscriber more than its buffer behind was not dropped")
+	}
+	if ids := feedEventIDs(receive(sub)); !slices.Equal(ids, []string{"1-1", "2-2"}) {
+		t.Errorf("dropped subscriber received %v, want the events that fit its buffer", ids)
+	}
+}
+
+func TestFeedWakesOnPublishedEvents(t *testing.T) {
+	dispatcher := NewDispatcher()
+	feed := NewFeed(&memoryOutbox{}, dispatcher, 10)
+
+	dispatcher.Publish(context.Background(), entity.OrderEvent{ID: "1"}, entity.OrderEvent{ID: "2"})
+	select {
+	case <-feed.wake:
+	default:
+		t.Fatal("publishing an event did not wake the feed")
+	}
+}
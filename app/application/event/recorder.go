@@ -0,0 +1,59 @@
+package event
+
+import (
+	"context"
+	"sync"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// Recorder captures published events, for checking what a service publishes:
+//
+//	dispatcher := event.NewDispatcher()
+//	recorder := event.NewRecorder(dispatcher)
+//	// ... exercise the service ...
+//	events := recorder.EventsOfType(entity.OrderEventType.Created)
+type Recorder struct {
+	mu     sync.Mutex
+	events []entity.OrderEvent
+}
+
+// NewRecorder creates a Recorder subscribed synchronously to all events of the dispatcher
+func NewRecorder(dispatcher *Dispatcher) *Recorder {
+	recorder := &Recorder{}
+	dispatcher.Subscribe("recorder", recorder.Handle)
+	return recorder
+}
+
+// Handle records an event; it can also be subscribed by hand
+func (r *Recorder) Handle(_ context.Context, event entity.OrderEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = append(r.events, event)
+	return nil
+}
+
+// Events returns the recorded events in publication order
+func (r *Recorder) Events() []entity.OrderEvent {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]entity.OrderEvent(nil), r.events...)
+}
+
+// EventsOfType returns the recorded events of the given type in publication order
+func (r *Recorder) EventsOfType(eventType string) []entity.OrderEvent {
+	var events []entity.OrderEvent
+	for _, event := range r.Events() {
+		if event.Type == eventType {
+			events = append(events, event)
+		}
+	}
+	return events
+}
+
+// Reset forgets the recorded events
+func (r *Recorder) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.events = nil
+}
@@ -0,0 +1,115 @@
+package event
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// eventIDs returns the IDs of events in order
+func eventIDs(events []entity.OrderEvent) []string {
+	ids := make([]string, len(events))
+	for i, event := range events {
+		ids[i] = event.ID
+	}
+	return ids
+}
+
+func TestRecorderRecordsPublishedEvents(t *testing.T) {
+	published := [][]entity.OrderEvent{
+		{{ID: "1", Type: entity.OrderEventType.Created, OrderID: "a"}},
+		{
+			{ID: "2", Type: entity.OrderEventType.StatusChanged, OrderID: "a"},
+			{ID: "3", Type: entity.OrderEventType.Created, OrderID: "b"},
+		},
+		{},
+		{{ID: "4", Type: entity.OrderEventType.Deleted, OrderID: "a"}},
+	}
+
+	tests := []struct {
+		eventType string
+		want      []string
+	}{
+		{"", []string{"1", "2", "3", "4"}},
+		{entity.OrderEventType.Created, []string{"1", "3"}},
+		{entity.OrderEventType.StatusChanged, []string{"2"}},
+		{entity.OrderEventType.Deleted, []string{"4"}},
+		{entity.OrderEventType.Purged, []string{}},
+	}
+
+	dispatcher := NewDispatcher()
+	recorder := NewRecorder(dispatcher)
+	for _, events := range published {
+		dispatcher.Publish(context.Background(), events...)
+	}
+
+	for _, tt := range tests {
+		var got []entity.OrderEvent
+		if tt.eventType == "" {
+			got = recorder.Events()
+		} else {
+			got = recorder.EventsOfType(tt.eventType)
+		}
+		if ids := eventIDs(got); !slices.Equal(ids, tt.want) {
+			t.Errorf("events of type %q = %v, want %v", tt.eventType, ids, tt.want)
+		}
+	}
+}
+
+func TestRecorderIsolatedFromOtherSubscribers(t *testing.T) {
+	tests := []struct {
+		name    string
+		handler Handler
+	}{
+		{"failing", func(context.Context, entity.OrderEvent) error { return errors.New("unavailable") }},
+		{"panicking", func(context.Context, entity.OrderEvent) error { panic("boom") }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dispatcher := NewDispatcher()
+			dispatcher.Subscribe(tt.name, tt.handler)
+			recorder := NewRecorder(dispatcher)
+
+			dispatcher.Publish(context.Background(),
+				entity.OrderEvent{ID: "1", Type: entity.OrderEventType.Created},
+				entity.OrderEvent{ID: "2", Type: entity.OrderEventType.Updated})
+			if ids := eventIDs(recorder.Events()); !slices.Equal(ids, []string{"1", "2"}) {
+				t.Errorf("recorded %v next to a %s subscriber, want [1 2]", ids, tt.name)
+			}
+		})
+	}
+}
+
+func TestRecorderEventsReturnsACopy(t *testing.T) {
+	dispatcher := NewDispatcher()
+	recorder := NewRecorder(dispatcher)
+	dispatcher.Publish(context.Background(), entity.OrderEvent{ID: "1", Type: entity.OrderEventType.Created})
+
+	events := recorder.Events()
+	events[0].ID = "changed"
+	if got := recorder.Events()[0].ID; got != "1" {
+		t.Errorf("changing the result of Events changed the recorded event to %q", got)
+	}
+}
+
+func TestRecorderResetAndUnsubscribe(t *testing.T) {
+	dispatcher := NewDispatcher()
+	recorder := NewRecorder(dispatcher)
+	dispatcher.Publish(context.Background(), entity.OrderEvent{ID: "1", Type: entity.OrderEventType.Created})
+
+	recorder.Reset()
+	if events := recorder.Events(); len(events) != 0 {
+		t.Fatalf("Events() after Reset = %v, want none", eventIDs(events))
+	}
+
+	dispatcher.Publish(context.Background(), entity.OrderEvent{ID: "2", Type: entity.OrderEventType.Updated})
+	dispatcher.Unsubscribe("recorder")
+	dispatcher.Publish(context.Background(), entity.OrderEvent{ID: "3", Type: entity.OrderEventType.Updated})
+	if ids := eventIDs(recorder.Events()); !slices.Equal(ids, []string{"2"}) {
+		t.Errorf("recorded %v, want only the event published before unsubscribing", ids)
+	}
+}
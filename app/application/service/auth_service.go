@@ -0,0 +1,55 @@
+package service
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// authClaims are the claims of a bearer token
+type authClaims struct {
+	Subject string `json:"sub"`
+	Role    string `json:"role"`
+	// Expiry is when the token expires, in seconds since the Unix epoch
+	Expiry int64 `json:"exp"`
+}
+
+// AuthService authenticates the bearer tokens of API callers. Tokens are JWTs signed with
+// HS256 by the identity provider sharing the secret, carrying the subject, role and expiry
+// of the principal.
+type AuthService struct {
+	secret []byte
+}
+
+// NewAuthService creates a new AuthService verifying tokens with secret; with an empty secret
+// no token is accepted
+func NewAuthService(secret []byte) *AuthService {
+	return &AuthService{
+		secret: secret,
+	}
+}
+
+// Authenticate returns the principal of a bearer token
+func (s *AuthService) Authenticate(token string) (*entity.Principal, error) {
+	if len(s.secret) == 0 {
+		return nil, fmt.Errorf("%w: bearer tokens are not accepted, auth.secret is not set", entity.ErrInvalidCredentials)
+	}
+
+	var claims authClaims
+	if err := verifyToken(s.secret, token, &claims); err != nil {
+		return nil, fmt.Errorf("%w: %v", entity.ErrInvalidCredentials, err)
+	}
+	switch {
+	case claims.Expiry == 0:
+		return nil, fmt.Errorf("%w: token has no expiry", entity.ErrInvalidCredentials)
+	case !time.Now().Before(time.Unix(claims.Expiry, 0)):
+		return nil, fmt.Errorf("%w: token expired", entity.ErrInvalidCredentials)
+	case claims.Subject == "":
+		return nil, fmt.Errorf("%w: token has no subject", entity.ErrInvalidCredentials)
+	case !entity.IsValidPrincipalRole(claims.Role):
+		return nil, fmt.Errorf("%w: unknown role %q", entity.ErrInvalidCredentials, claims.Role)
+	}
+
+	return &entity.Principal{Subject: claims.Subject, Role: claims.Role}, nil
+}
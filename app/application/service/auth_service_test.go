@@ -0,0 +1,62 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+func TestAuthServiceAuthenticate(t *testing.T) {
+	secret := []byte("test-secret")
+	expiry := time.Now().Add(time.Hour).Unix()
+	sign := func(secret []byte, claims authClaims) string {
+		token, err := signToken(secret, claims)
+		if err != nil {
+			t.Fatalf("signToken() error = %v", err)
+		}
+		return token
+	}
+	valid := sign(secret, authClaims{Subject: "511", Role: entity.PrincipalRole.Customer, Expiry: expiry})
+	parts := strings.Split(valid, ".")
+	unsigned := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none"}`)) + "." + parts[1] + "."
+
+	tests := []struct {
+		name   string
+		secret []byte
+		token  string
+		want   *entity.Principal
+	}{
+		{"customer", secret, valid, &entity.Principal{Subject: "511", Role: entity.PrincipalRole.Customer}},
+		{"admin", secret, sign(secret, authClaims{Subject: "ops", Role: entity.PrincipalRole.Admin, Expiry: expiry}),
+			&entity.Principal{Subject: "ops", Role: entity.PrincipalRole.Admin}},
+		{"other secret", secret, sign([]byte("other-secret"), authClaims{Subject: "511", Role: entity.PrincipalRole.Customer, Expiry: expiry}), nil},
+		{"no secret configured", nil, valid, nil},
+		{"expired", secret, sign(secret, authClaims{Subject: "511", Role: entity.PrincipalRole.Customer, Expiry: time.Now().Add(-time.Second).Unix()}), nil},
+		{"no expiry", secret, sign(secret, authClaims{Subject: "511", Role: entity.PrincipalRole.Customer}), nil},
+		{"no subject", secret, sign(secret, authClaims{Role: entity.PrincipalRole.Customer, Expiry: expiry}), nil},
+		{"unknown role", secret, sign(secret, authClaims{Subject: "511", Role: "root", Expiry: expiry}), nil},
+		{"unsigned", secret, unsigned, nil},
+		{"tampered claims", secret, parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"512","role":"customer","exp":9999999999}`)) + "." + parts[2], nil},
+		{"not a JWT", secret, "token", nil},
+		{"empty", secret, "", nil},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			principal, err := NewAuthService(tt.secret).Authenticate(tt.token)
+			if tt.want == nil {
+				if !errors.Is(err, entity.ErrInvalidCredentials) || !errors.Is(err, entity.ErrUnauthenticated) {
+					t.Fatalf("Authenticate() = %+v, %v, want ErrInvalidCredentials", principal, err)
+				}
+				return
+			}
+			if err != nil || *principal != *tt.want {
+				t.Fatalf("Authenticate() = %+v, %v, want %+v", principal, err, tt.want)
+			}
+		})
+	}
+}
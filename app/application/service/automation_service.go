@@ -0,0 +1,189 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// automationLock is the lock held by the replica running the automation rules
+const automationLock = "order-automation"
+
+// ValidateAutomationRules checks that every rule names a known status and action, and
+// that transition rules follow the order status transitions
+func ValidateAutomationRules(rules []entity.AutomationRule) error {
+	names := make(map[string]bool, len(rules))
+	for _, rule := range rules {
+		if rule.Name == "" {
+			return fmt.Errorf("%w: every rule needs a name", entity.ErrInvalidAutomationRule)
+		}
+		if names[rule.Name] {
+			return fmt.Errorf("%w: duplicate rule name %s", entity.ErrInvalidAutomationRule, rule.Name)
+		}
+		names[rule.Name] = true
+
+		if !entity.IsValidOrderStatus(rule.Status) {
+			return fmt.Errorf("%w: %s has unknown status %q", entity.ErrInvalidAutomationRule, rule.Name, rule.Status)
+		}
+		if rule.After <= 0 {
+			return fmt.Errorf("%w: %s needs a positive after duration", entity.ErrInvalidAutomationRule, rule.Name)
+		}
+
+		switch rule.Action {
+		case entity.AutomationAction.Transition:
+			if rule.TargetStatus == rule.Status || !entity.CanTransitionOrderStatus(rule.Status, rule.TargetStatus) {
+				return fmt.Errorf("%w: %s cannot move orders from %s to %q", entity.ErrInvalidAutomationRule, rule.Name, rule.Status, rule.TargetStatus)
+			}
+		case entity.AutomationAction.Flag:
+			if rule.TargetStatus != "" {
+				return fmt.Errorf("%w: %s flags orders and takes no target status", entity.ErrInvalidAutomationRule, rule.Name)
+			}
+		default:
+			return fmt.Errorf("%w: %s has unknown action %q", entity.ErrInvalidAutomationRule, rule.Name, rule.Action)
+		}
+	}
+	return nil
+}
+
+// AutomationService applies the configured rules to orders that have stayed in a status for
+// too long. Changes go through OrderService, so they are validated and show up in the status
+// history and the outbox like any other change. Only the replica holding the shared lock runs
+// the rules.
+type AutomationService struct {
+	orderRepo    repository.OrderRepository
+	orderService *OrderService
+	lockRepo     repository.LockRepository
+	rules        []entity.AutomationRule
+	batchSize    int
+}
+
+// NewAutomationService creates a new AutomationService for rules checked by ValidateAutomationRules
+func NewAutomationService(orderRepo repository.OrderRepository, orderService *OrderService, lockRepo repository.LockRepository,
+	rules []entity.AutomationRule, batchSize int) *AutomationService {
+	return &AutomationService{
+		orderRepo:    orderRepo,
+		orderService: orderService,
+		lockRepo:     lockRepo,
+		rules:        rules,
+		batchSize:    batchSize,
+	}
+}
+
+// GetRules returns the configured rules
+func (s *AutomationService) GetRules() []entity.AutomationRule {
+	return s.rules
+}
+
+// RunOnce applies every rule once, unless another replica is running them
+func (s *AutomationService) RunOnce(ctx context.Context) (*entity.AutomationRun, error) {
+	run := &entity.AutomationRun{StartedAt: time.Now(), Results: []entity.AutomationRuleResult{}}
+
+	release, acquired, err := s.lockRepo.TryLock(ctx, automationLock)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		run.Skipped = true
+		run.FinishedAt = time.Now()
+		return run, nil
+	}
+	defer release()
+
+	for _, rule := range s.rules {
+		result, err := s.apply(ctx, rule)
+		run.Results = append(run.Results, result)
+		if err != nil {
+			return run, fmt.Errorf("rule %s: %w", rule.Name, err)
+		}
+	}
+
+	run.FinishedAt = time.Now()
+	return run, nil
+}
+
+// Run applies the rules every interval until ctx is cancelled
+func (s *AutomationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			run, err := s.RunOnce(ctx)
+			if err != nil {
+				log.Printf("Failed to run order automation: %v", err)
+				continue
+			}
+			for _, result := range run.Results {
+				if result.Matched > 0 {
+					log.Printf("Automation rule %s: matched=%d applied=%d failed=%d", result.Rule, result.Matched, result.Applied, result.Failed)
+				}
+			}
+		}
+	}
+}
+
+// apply runs a rule over the orders it matches, batch by batch. Orders that fail are counted
+// and skipped; a failing query stops the rule.
+func (s *AutomationService) apply(ctx context.Context, rule entity.AutomationRule) (entity.AutomationRuleResult, error) {
+	result := entity.AutomationRuleResult{Rule: rule.Name}
+	actor := "system:automation/" + rule.Name
+	before := time.Now().Add(-rule.After)
+
+	unflaggedRule := ""
+	if rule.Action == entity.AutomationAction.Flag {
+		unflaggedRule = rule.Name
+	}
+
+	// Failed orders still match the rule, so they are skipped by ID within this run
+	failed := make(map[string]bool)
+	for {
+		limit := s.batchSize + len(failed)
+		orders, err := s.orderRepo.FindInStatusBefore(ctx, rule.Status, before, unflaggedRule, limit)
+		if err != nil {
+			return result, err
+		}
+
+		progressed := false
+		for i := range orders {
+			order := &orders[i]
+			if failed[order.ID] {
+				continue
+			}
+			result.Matched++
+
+			if err := s.applyToOrder(ctx, rule, order, actor); err != nil {
+				result.Failed++
+				failed[order.ID] = true
+				if !errors.Is(err, entity.ErrOrderVersionConflict) {
+					log.Printf("Automation rule %s failed on order %s: %v", rule.Name, order.ID, err)
+				}
+				continue
+			}
+			result.Applied++
+			progressed = true
+		}
+
+		if !progressed || len(orders) < limit {
+			return result, nil
+		}
+	}
+}
+
+// applyToOrder moves or flags a single order; transitions only apply to the version read
+func (s *AutomationService) applyToOrder(ctx context.Context, rule entity.AutomationRule, order *entity.Order, actor string) error {
+	if rule.Action == entity.AutomationAction.Flag {
+		_, err := s.orderService.FlagOrder(ctx, order, rule.Name, rule.Reason, actor)
+		return err
+	}
+
+	update := &entity.Order{ID: order.ID, Status: rule.TargetStatus, Version: order.Version}
+	return s.orderService.UpdateOrder(ctx, update, actor, rule.Reason)
+}
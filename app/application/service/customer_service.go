@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"net/mail"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// CustomerService defines the service for customer operations
+// The name and email of a customer are copied onto its orders; changing them updates every
+// order that carries them, with an event per order like any other order update.
+type CustomerService struct {
+	customerRepo repository.CustomerRepository
+	orderRepo    repository.OrderRepository
+	transactor   repository.Transactor
+	dispatcher   *event.Dispatcher
+}
+
+// NewCustomerService creates a new CustomerService
+func NewCustomerService(customerRepo repository.CustomerRepository, orderRepo repository.OrderRepository,
+	transactor repository.Transactor, dispatcher *event.Dispatcher) *CustomerService {
+	return &CustomerService{
+		customerRepo: customerRepo,
+		orderRepo:    orderRepo,
+		transactor:   transactor,
+		dispatcher:   dispatcher,
+	}
+}
+
+// GetAllCustomers retrieves all customers
+func (s *CustomerService) GetAllCustomers(ctx context.Context) ([]entity.Customer, error) {
+	return s.customerRepo.FindAll(ctx)
+}
+
+// GetCustomerByID retrieves a customer by its ID
+func (s *CustomerService) GetCustomerByID(ctx context.Context, id string) (*entity.Customer, error) {
+	customer, err := s.customerRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if customer == nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrCustomerNotFound, id)
+	}
+	return customer, nil
+}
+
+// GetCustomersByIDs retrieves several customers at once, keyed by ID; missing ids are absent
+func (s *CustomerService) GetCustomersByIDs(ctx context.Context, ids []string) (map[string]entity.Customer, error) {
+	return s.customerRepo.FindByIDs(ctx, ids)
+}
+
+// CreateCustomer creates a new customer, generating an ID when none is given
+func (s *CustomerService) CreateCustomer(ctx context.Context, customer *entity.Customer) error {
+	if customer.ID == "" {
+		customer.ID = uuid.NewString()
+	}
+	if err := validateCustomer(customer); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	customer.CreatedAt = now
+	customer.UpdatedAt = now
+
+	return s.customerRepo.Create(ctx, customer)
+}
+
+// UpdateCustomer updates an existing customer on behalf of actor. Its orders, including
+// soft-deleted ones, pick up the new name and email in the same transaction, each with an
+// order updated event in the outbox; the events are published once it commits.
+func (s *CustomerService) UpdateCustomer(ctx context.Context, customer *entity.Customer, actor string) error {
+	existingCustomer, err := s.GetCustomerByID(ctx, customer.ID)
+	if err != nil {
+		return err
+	}
+
+	// Update only provided fields
+	if customer.Name != "" {
+		existingCustomer.Name = customer.Name
+	}
+	if customer.Email != "" {
+		existingCustomer.Email = customer.Email
+	}
+	if customer.Phone != "" {
+		existingCustomer.Phone = customer.Phone
+	}
+	if err := validateCustomer(existingCustomer); err != nil {
+		return err
+	}
+
+	existingCustomer.UpdatedAt = time.Now()
+	var events []entity.OrderEvent
+	err = s.transactor.InTransaction(ctx, func(ctx context.Context) error {
+		events = nil
+		if err := s.customerRepo.Update(ctx, existingCustomer); err != nil {
+			return err
+		}
+
+		orders, err := s.orderRepo.FindWithStaleCustomer(ctx, existingCustomer)
+		if err != nil {
+			return err
+		}
+		for i := range orders {
+			order := &orders[i]
+			order.CustomerName = existingCustomer.Name
+			order.CustomerEmail = existingCustomer.Email
+			order.UpdatedAt = existingCustomer.UpdatedAt
+			event := newOrderEvent(entity.OrderEventType.Updated, order, actor)
+			// The repository writes the row only at its current version and increments it
+			event.Order.Version++
+
+			if err := s.orderRepo.UpdateCustomerDetails(ctx, order, []entity.OrderEvent{event}); err != nil {
+				return err
+			}
+			events = append(events, event)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	s.dispatcher.Publish(ctx, events...)
+
+	*customer = *existingCustomer
+	return nil
+}
+
+// DeleteCustomer deletes a customer without orders
+func (s *CustomerService) DeleteCustomer(ctx context.Context, id string) error {
+	if _, err := s.GetCustomerByID(ctx, id); err != nil {
+		return err
+	}
+
+	hasOrders, err := s.customerRepo.HasOrders(ctx, id)
+	if err != nil {
+		return err
+	}
+	if hasOrders {
+		return fmt.Errorf("%w: %s", entity.ErrCustomerHasOrders, id)
+	}
+
+	return s.customerRepo.Delete(ctx, id)
+}
+
+// validateCustomer normalizes and validates the fields of a customer
+func validateCustomer(customer *entity.Customer) error {
+	customer.Name = strings.TrimSpace(customer.Name)
+	customer.Email = strings.TrimSpace(customer.Email)
+	customer.Phone = strings.TrimSpace(customer.Phone)
+
+	if customer.Name == "" {
+		return entity.NewFieldError(entity.ErrInvalidCustomer, "name", "is required")
+	}
+	if customer.Email == "" {
+		return entity.NewFieldError(entity.ErrInvalidCustomer, "email", "is required")
+	}
+	if address, err := mail.ParseAddress(customer.Email); err != nil || address.Address != customer.Email {
+		return entity.NewFieldError(entity.ErrInvalidCustomer, "email", fmt.Sprintf("%q is not an email address", customer.Email))
+	}
+	return nil
+}
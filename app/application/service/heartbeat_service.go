@@ -0,0 +1,244 @@
+package service
+
+import (
+	"context"
+	"log"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// ErrInvalidLatencyReport is returned when a latency report is requested with invalid parameters
+var ErrInvalidLatencyReport = entity.NewError(entity.ErrValidation, "invalid latency report request")
+
+// latencyBuckets are the upper bounds of the replication latency histogram
+var latencyBuckets = []time.Duration{
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+	2 * time.Second,
+	5 * time.Second,
+	10 * time.Second,
+	30 * time.Second,
+	time.Minute,
+}
+
+// LatencySample is a single end-to-end replication latency measurement
+type LatencySample struct {
+	Seq       int64     `json:"seq"`
+	SentAt    time.Time `json:"sentAt"`
+	LatencyMs int64     `json:"latencyMs"`
+	TimedOut  bool      `json:"timedOut"`
+}
+
+// LatencyBucket is a cumulative histogram bucket; an empty Le is the +Inf bucket
+type LatencyBucket struct {
+	Le    string `json:"le"`
+	Count int    `json:"count"`
+}
+
+// LatencyReport summarizes the samples currently in the rolling window
+type LatencyReport struct {
+	Samples   int             `json:"samples"`
+	TimedOut  int             `json:"timedOut"`
+	SLOMs     int64           `json:"sloMs"`
+	WithinSLO float64         `json:"withinSlo"`
+	P50Ms     int64           `json:"p50Ms"`
+	P95Ms     int64           `json:"p95Ms"`
+	P99Ms     int64           `json:"p99Ms"`
+	MaxMs     int64           `json:"maxMs"`
+	Buckets   []LatencyBucket `json:"buckets"`
+	Recent    []LatencySample `json:"recent"`
+}
+
+// HeartbeatService periodically upserts a heartbeat row in PostgreSQL and measures
+// how long it takes to become visible in Elasticsearch. The writes also keep the
+// replication slot advancing while the captured tables are idle.
+type HeartbeatService struct {
+	heartbeatRepo       repository.HeartbeatRepository
+	heartbeatSearchRepo repository.HeartbeatSearchRepository
+	id                  string
+	timeout             time.Duration
+	pollInterval        time.Duration
+	slo                 time.Duration
+
+	mu      sync.Mutex
+	samples []LatencySample
+	next    int
+	full    bool
+}
+
+// NewHeartbeatService creates a new HeartbeatService keeping the last size samples
+func NewHeartbeatService(heartbeatRepo repository.HeartbeatRepository, heartbeatSearchRepo repository.HeartbeatSearchRepository,
+	id string, timeout, pollInterval, slo time.Duration, size int) *HeartbeatService {
+	if size <= 0 {
+		size = 1
+	}
+	return &HeartbeatService{
+		heartbeatRepo:       heartbeatRepo,
+		heartbeatSearchRepo: heartbeatSearchRepo,
+		id:                  id,
+		timeout:             timeout,
+		pollInterval:        pollInterval,
+		slo:                 slo,
+		samples:             make([]LatencySample, size),
+	}
+}
+
+// Probe writes one heartbeat and waits until it is visible in the search index or the timeout expires
+func (s *HeartbeatService) Probe(ctx context.Context) (*LatencySample, error) {
+	sentAt := time.Now()
+	heartbeat := &entity.Heartbeat{
+		ID:     s.id,
+		Seq:    sentAt.UnixNano(),
+		BeatAt: sentAt,
+	}
+	if err := s.heartbeatRepo.Upsert(ctx, heartbeat); err != nil {
+		return nil, err
+	}
+
+	sample := LatencySample{Seq: heartbeat.Seq, SentAt: sentAt}
+
+	probeCtx, cancel := context.WithTimeout(ctx, s.timeout)
+	defer cancel()
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		replicated, err := s.heartbeatSearchRepo.FindByID(probeCtx, s.id)
+		if err != nil && probeCtx.Err() == nil {
+			log.Printf("Failed to read heartbeat from search index: %v", err)
+		}
+		if replicated != nil && replicated.Seq >= heartbeat.Seq {
+			sample.LatencyMs = time.Since(sentAt).Milliseconds()
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-probeCtx.Done():
+			sample.LatencyMs = s.timeout.Milliseconds()
+			sample.TimedOut = true
+		case <-ticker.C:
+			continue
+		}
+		break
+	}
+
+	s.record(sample)
+	return &sample, nil
+}
+
+// Run probes every interval until ctx is cancelled
+func (s *HeartbeatService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			sample, err := s.Probe(ctx)
+			if err != nil {
+				log.Printf("Failed to probe replication latency: %v", err)
+				continue
+			}
+			if sample.TimedOut {
+				log.Printf("Heartbeat %d not visible in search index after %s", sample.Seq, s.timeout)
+			}
+		}
+	}
+}
+
+// Report summarizes the rolling window of latency samples, returning at most recent samples newest
+// first; recent is clamped to the samples in the window
+func (s *HeartbeatService) Report(recent int) *LatencyReport {
+	samples := s.snapshot()
+
+	report := &LatencyReport{
+		Samples: len(samples),
+		SLOMs:   s.slo.Milliseconds(),
+		Buckets: make([]LatencyBucket, len(latencyBuckets)+1),
+	}
+	for i, bound := range latencyBuckets {
+		report.Buckets[i].Le = bound.String()
+	}
+
+	latencies := make([]int64, 0, len(samples))
+	withinSLO := 0
+	for _, sample := range samples {
+		if sample.TimedOut {
+			report.TimedOut++
+		} else {
+			latencies = append(latencies, sample.LatencyMs)
+			if sample.LatencyMs <= report.SLOMs {
+				withinSLO++
+			}
+		}
+		for i, bound := range latencyBuckets {
+			if !sample.TimedOut && sample.LatencyMs <= bound.Milliseconds() {
+				report.Buckets[i].Count++
+			}
+		}
+		report.Buckets[len(latencyBuckets)].Count++
+	}
+
+	if len(samples) > 0 {
+		report.WithinSLO = float64(withinSLO) / float64(len(samples))
+	}
+	if len(latencies) > 0 {
+		sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+		report.P50Ms = percentile(latencies, 0.50)
+		report.P95Ms = percentile(latencies, 0.95)
+		report.P99Ms = percentile(latencies, 0.99)
+		report.MaxMs = latencies[len(latencies)-1]
+	}
+
+	recent = min(max(recent, 0), len(samples))
+	report.Recent = make([]LatencySample, 0, recent)
+	for i := len(samples) - 1; i >= len(samples)-recent; i-- {
+		report.Recent = append(report.Recent, samples[i])
+	}
+
+	return report
+}
+
+// record adds a sample to the ring buffer, overwriting the oldest one when full
+func (s *HeartbeatService) record(sample LatencySample) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	s.samples[s.next] = sample
+	s.next = (s.next + 1) % len(s.samples)
+	if s.next == 0 {
+		s.full = true
+	}
+}
+
+// snapshot returns the samples in the ring buffer, oldest first
+func (s *HeartbeatService) snapshot() []LatencySample {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if !s.full {
+		return append([]LatencySample(nil), s.samples[:s.next]...)
+	}
+	return append(append([]LatencySample(nil), s.samples[s.next:]...), s.samples[:s.next]...)
+}
+
+// percentile returns the nearest-rank percentile of sorted values
+func percentile(sorted []int64, p float64) int64 {
+	rank := int(p*float64(len(sorted))+0.5) - 1
+	if rank < 0 {
+		rank = 0
+	}
+	if rank >= len(sorted) {
+		rank = len(sorted) - 1
+	}
+	return sorted[rank]
+}
@@ -0,0 +1,94 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// IdempotencyService makes requests sent with an idempotency key take effect once. The first
+// request reserves the key, and its response is stored for the TTL so retries can replay it.
+// A reservation that does not complete within the lease is presumed lost with its replica and
+// may be taken over by a retry, so the lease must outlast the slowest request.
+type IdempotencyService struct {
+	idempotencyRepo repository.IdempotencyRepository
+	ttl             time.Duration
+	lease           time.Duration
+}
+
+// NewIdempotencyService creates a new IdempotencyService
+func NewIdempotencyService(idempotencyRepo repository.IdempotencyRepository, ttl, lease time.Duration) *IdempotencyService {
+	return &IdempotencyService{
+		idempotencyRepo: idempotencyRepo,
+		ttl:             ttl,
+		lease:           lease,
+	}
+}
+
+// Begin reserves key in scope for the request with requestHash. It returns the stored record
+// when the same request completed before, ErrIdempotencyKeyReused when the key belongs to
+// another request and ErrIdempotencyKeyInProgress while the first request is still running.
+// A nil record means the caller should handle the request and then Complete or Release it.
+func (s *IdempotencyService) Begin(ctx context.Context, scope, key, requestHash string) (*entity.IdempotencyRecord, error) {
+	now := time.Now()
+	existing, err := s.idempotencyRepo.Reserve(ctx, &entity.IdempotencyRecord{
+		Scope:          scope,
+		Key:            key,
+		RequestHash:    requestHash,
+		CreatedAt:      now,
+		LeaseExpiresAt: now.Add(s.lease),
+		ExpiresAt:      now.Add(s.ttl),
+	})
+	if err != nil || existing == nil {
+		return nil, err
+	}
+
+	switch {
+	case existing.RequestHash != requestHash:
+		return nil, entity.ErrIdempotencyKeyReused
+	case !existing.Completed:
+		return nil, entity.ErrIdempotencyKeyInProgress
+	default:
+		return existing, nil
+	}
+}
+
+// Complete stores the response of a request begun with Begin
+func (s *IdempotencyService) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	return s.idempotencyRepo.Complete(ctx, record)
+}
+
+// Release frees the key of a request begun with Begin that should not be replayed
+func (s *IdempotencyService) Release(ctx context.Context, scope, key string) error {
+	return s.idempotencyRepo.Release(ctx, scope, key)
+}
+
+// Prune deletes expired records
+func (s *IdempotencyService) Prune(ctx context.Context) (int64, error) {
+	return s.idempotencyRepo.DeleteExpired(ctx, time.Now())
+}
+
+// Run prunes expired records every interval until ctx is cancelled
+func (s *IdempotencyService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.Prune(ctx)
+			if err != nil {
+				log.Printf("Failed to prune idempotency keys: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Pruned %d idempotency keys", deleted)
+			}
+		}
+	}
+}
@@ -2,22 +2,55 @@ package service
 
 import (
 	"context"
-	"errors"
+	"fmt"
+	"log"
+	"strings"
 	"time"
 
+	"github.com/google/uuid"
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
 	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
 	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
 )
 
+const (
+	// purgeBatchSize is the number of deleted orders purged per query by the retention job
+	purgeBatchSize = 100
+
+	// purgeActor is recorded on events of orders purged by the retention job
+	purgeActor = "system:retention"
+
+	// defaultOrderQueryLimit is the page size of order queries that do not set one
+	defaultOrderQueryLimit = 50
+
+	// maxOrderQueryLimit is the largest page size an order query may ask for
+	maxOrderQueryLimit = 500
+)
+
 // OrderService defines the service for order operations
+// Every change is written with its events to the outbox and, once committed, published
+// to the in-process dispatcher.
 type OrderService struct {
-	orderRepo repository.OrderRepository
+	orderRepo       repository.OrderRepository
+	orderSearchRepo repository.OrderSearchRepository
+	customerRepo    repository.CustomerRepository
+	transactor      repository.Transactor
+	dispatcher      *event.Dispatcher
+	// allowClientIDs lets callers supply id and orderId
+	allowClientIDs bool
 }
 
 // NewOrderService creates a new OrderService
-func NewOrderService(orderRepo repository.OrderRepository) *OrderService {
+func NewOrderService(orderRepo repository.OrderRepository, orderSearchRepo repository.OrderSearchRepository,
+	customerRepo repository.CustomerRepository, transactor repository.Transactor, dispatcher *event.Dispatcher,
+	allowClientIDs bool) *OrderService {
 	return &OrderService{
-		orderRepo: orderRepo,
+		orderRepo:       orderRepo,
+		orderSearchRepo: orderSearchRepo,
+		transactor:      transactor,
+		customerRepo:    customerRepo,
+		dispatcher:      dispatcher,
+		allowClientIDs:  allowClientIDs,
 	}
 }
 
@@ -26,6 +59,60 @@ func (s *OrderService) GetAllOrders(ctx context.Context) ([]entity.Order, error)
 	return s.orderRepo.FindAll(ctx)
 }
 
+// GetOrders retrieves the page of orders selected by query from PostgreSQL
+func (s *OrderService) GetOrders(ctx context.Context, query entity.OrderQuery) (*entity.OrderPage, error) {
+	if err := normalizeOrderQuery(&query); err != nil {
+		return nil, err
+	}
+	orders, total, err := s.orderRepo.Find(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.OrderPage{Orders: orders, Total: total, Limit: query.Limit, Offset: query.Offset}, nil
+}
+
+// SearchOrders retrieves the page of orders selected by query from the search index, which
+// lags PostgreSQL by the replication delay
+func (s *OrderService) SearchOrders(ctx context.Context, query entity.OrderQuery) (*entity.OrderPage, error) {
+	if err := normalizeOrderQuery(&query); err != nil {
+		return nil, err
+	}
+	orders, total, err := s.orderSearchRepo.Search(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	return &entity.OrderPage{Orders: orders, Total: total, Limit: query.Limit, Offset: query.Offset}, nil
+}
+
+// normalizeOrderQuery validates query and fills in its default sort and page size
+func normalizeOrderQuery(query *entity.OrderQuery) error {
+	for _, status := range query.Statuses {
+		if !entity.IsValidOrderStatus(status) {
+			return entity.NewFieldError(entity.ErrInvalidOrderStatus, "status", fmt.Sprintf("%q is not an order status", status))
+		}
+	}
+	if query.CreatedFrom != nil && query.CreatedTo != nil && !query.CreatedFrom.Before(*query.CreatedTo) {
+		return entity.NewFieldError(entity.ErrInvalidOrderQuery, "createdFrom", "must be before createdTo")
+	}
+	if query.UpdatedFrom != nil && query.UpdatedTo != nil && !query.UpdatedFrom.Before(*query.UpdatedTo) {
+		return entity.NewFieldError(entity.ErrInvalidOrderQuery, "updatedFrom", "must be before updatedTo")
+	}
+	if query.Limit < 0 || query.Limit > maxOrderQueryLimit {
+		return entity.NewFieldError(entity.ErrInvalidOrderQuery, "limit", fmt.Sprintf("must be between 1 and %d", maxOrderQueryLimit))
+	}
+	if query.Offset < 0 {
+		return entity.NewFieldError(entity.ErrInvalidOrderQuery, "offset", "must not be negative")
+	}
+
+	if query.Sort == "" {
+		query.Sort = entity.OrderSortField.CreatedAt
+	}
+	if query.Limit == 0 {
+		query.Limit = defaultOrderQueryLimit
+	}
+	return nil
+}
+
 // GetOrderByID retrieves an order by its ID
 func (s *OrderService) GetOrderByID(ctx context.Context, id string) (*entity.Order, error) {
 	order, err := s.orderRepo.FindByID(ctx, id)
@@ -33,74 +120,630 @@ func (s *OrderService) GetOrderByID(ctx context.Context, id string) (*entity.Ord
 		return nil, err
 	}
 	if order == nil {
-		return nil, errors.New("order not found")
+		return nil, fmt.Errorf("%w: %s", entity.ErrOrderNotFound, id)
 	}
 	return order, nil
 }
 
 // GetOrdersByStatus retrieves orders by status
 func (s *OrderService) GetOrdersByStatus(ctx context.Context, status string) ([]entity.Order, error) {
+	if !entity.IsValidOrderStatus(status) {
+		return nil, entity.NewFieldError(entity.ErrInvalidOrderStatus, "status", fmt.Sprintf("%q is not an order status", status))
+	}
 	return s.orderRepo.FindByStatus(ctx, status)
 }
 
-// CreateOrder creates a new order
-func (s *OrderService) CreateOrder(ctx context.Context, order *entity.Order) error {
+// GetOrderTransitions retrieves an order and the statuses it may move to next
+func (s *OrderService) GetOrderTransitions(ctx context.Context, id string) (*entity.Order, []string, error) {
+	order, err := s.GetOrderByID(ctx, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	return order, entity.NextOrderStatuses(order.Status), nil
+}
+
+// GetOrderHistory retrieves the status changes of an order, oldest first
+func (s *OrderService) GetOrderHistory(ctx context.Context, id string) ([]entity.OrderStatusChange, error) {
+	if _, err := s.GetOrderByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.FindStatusHistory(ctx, id)
+}
+
+// GetOrderFlags retrieves the flags raised on an order by automation rules, oldest first
+func (s *OrderService) GetOrderFlags(ctx context.Context, id string) ([]entity.OrderFlag, error) {
+	if _, err := s.GetOrderByID(ctx, id); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.FindFlags(ctx, id)
+}
+
+// GetOrdersHistory retrieves the status changes of several orders at once, keyed by order ID
+// and oldest first
+func (s *OrderService) GetOrdersHistory(ctx context.Context, ids []string) (map[string][]entity.OrderStatusChange, error) {
+	return s.orderRepo.FindStatusHistoryByOrderIDs(ctx, ids)
+}
+
+// GetOrdersFlags retrieves the flags of several orders at once, keyed by order ID and oldest first
+func (s *OrderService) GetOrdersFlags(ctx context.Context, ids []string) (map[string][]entity.OrderFlag, error) {
+	return s.orderRepo.FindFlagsByOrderIDs(ctx, ids)
+}
+
+// FlagOrder flags an order for follow-up on behalf of actor. An order is flagged at most once
+// per rule; flagged reports whether this call flagged it.
+func (s *OrderService) FlagOrder(ctx context.Context, order *entity.Order, rule, reason, actor string) (bool, error) {
+	flag := &entity.OrderFlag{
+		ID:        uuid.NewString(),
+		OrderID:   order.ID,
+		Rule:      rule,
+		Reason:    reason,
+		FlaggedAt: time.Now(),
+	}
+	event := newOrderEvent(entity.OrderEventType.Flagged, order, actor)
+	event.OccurredAt = flag.FlaggedAt
+	event.Reason = reason
+
+	flagged, err := s.orderRepo.Flag(ctx, flag, []entity.OrderEvent{event})
+	if err != nil || !flagged {
+		return false, err
+	}
+
+	s.publish(ctx, event)
+	return true, nil
+}
+
+// CreateOrder creates a new order, recording its initial status as set by actor
+func (s *OrderService) CreateOrder(ctx context.Context, order *entity.Order, actor string) error {
 	// Set timestamps
 	now := time.Now()
 	order.CreatedAt = now
 	order.UpdatedAt = now
+	order.Version = 1
 
-	// Validate order
-	if order.ID == "" || order.OrderID == "" || order.CustomerID == "" {
-		return errors.New("id, orderId and customerId are required")
+	// Validate order and assign its identifiers
+	if order.CustomerID == "" {
+		return entity.NewFieldError(entity.ErrInvalidOrder, "customerId", "is required")
+	}
+	if err := s.assignIdentifiers(ctx, order); err != nil {
+		return err
 	}
 
 	// Set default status if not provided
 	if order.Status == "" {
 		order.Status = entity.OrderStatus.New
 	}
+	if !entity.IsValidOrderStatus(order.Status) {
+		return entity.NewFieldError(entity.ErrInvalidOrderStatus, "status", fmt.Sprintf("%q is not an order status", order.Status))
+	}
+	if !entity.IsInitialOrderStatus(order.Status) {
+		return fmt.Errorf("%w: orders cannot be created as %s", entity.ErrInvalidStatusTransition, order.Status)
+	}
 
-	return s.orderRepo.Create(ctx, order)
+	// Price items and compute the order total
+	if err := priceOrder(order, order.Items); err != nil {
+		return err
+	}
+
+	return s.inTransaction(ctx, func(ctx context.Context) error {
+		// Copy the customer details searched on in the index. The customer is read FOR SHARE,
+		// so its details cannot change before the order commits.
+		if err := s.assignCustomer(ctx, order, order.CustomerID); err != nil {
+			return err
+		}
+
+		change := newStatusChange(order, "", actor, "created")
+		event := newOrderEvent(entity.OrderEventType.Created, order, actor)
+		if err := s.orderRepo.Create(ctx, order, change, []entity.OrderEvent{event}); err != nil {
+			return err
+		}
+		s.publish(ctx, event)
+		return nil
+	})
 }
 
-// UpdateOrder updates an existing order
-func (s *OrderService) UpdateOrder(ctx context.Context, order *entity.Order) error {
-	// Check if order exists
-	existingOrder, err := s.orderRepo.FindByID(ctx, order.ID)
+// UpdateOrder updates the provided fields of an existing order; empty fields keep their value.
+// A non-zero order.Version is the version the caller expects to update; ErrOrderVersionConflict
+// is returned when the order has moved on. The new version is set on order. A status change is
+// recorded in the order history with the given actor and reason.
+func (s *OrderService) UpdateOrder(ctx context.Context, order *entity.Order, actor, reason string) error {
+	existingOrder, err := s.findOrderAtVersion(ctx, order.ID, order.Version)
 	if err != nil {
 		return err
 	}
-	if existingOrder == nil {
-		return errors.New("order not found")
-	}
 
 	// Update only provided fields
+	replacement := *existingOrder
 	if order.OrderID != "" {
-		existingOrder.OrderID = order.OrderID
+		replacement.OrderID = order.OrderID
 	}
 	if order.CustomerID != "" {
-		existingOrder.CustomerID = order.CustomerID
+		replacement.CustomerID = order.CustomerID
 	}
 	if order.Status != "" {
-		existingOrder.Status = order.Status
+		replacement.Status = order.Status
+	}
+	if order.Items != nil {
+		replacement.Items = order.Items
+	}
+
+	if err := s.saveOrder(ctx, existingOrder, &replacement, actor, reason); err != nil {
+		return err
+	}
+	*order = *existingOrder
+	return nil
+}
+
+// ReplaceOrder replaces the writable fields of an existing order: customerId and status are
+// required, and missing items clear the order's items. orderId may be omitted to keep it.
+// Read-only fields such as totals and timestamps are ignored. Versions, status transitions
+// and history are handled as by UpdateOrder.
+func (s *OrderService) ReplaceOrder(ctx context.Context, order *entity.Order, actor, reason string) error {
+	existingOrder, err := s.findOrderAtVersion(ctx, order.ID, order.Version)
+	if err != nil {
+		return err
+	}
+	if order.CustomerID == "" {
+		return entity.NewFieldError(entity.ErrInvalidOrder, "customerId", "is required")
+	}
+	if order.Status == "" {
+		return entity.NewFieldError(entity.ErrInvalidOrder, "status", "is required")
+	}
+
+	replacement := *order
+	if replacement.OrderID == "" {
+		replacement.OrderID = existingOrder.OrderID
+	}
+
+	if err := s.saveOrder(ctx, existingOrder, &replacement, actor, reason); err != nil {
+		return err
+	}
+	*order = *existingOrder
+	return nil
+}
+
+// findOrderAtVersion retrieves an order, failing with a conflict when version is non-zero and
+// the order is at another version
+func (s *OrderService) findOrderAtVersion(ctx context.Context, id string, version int64) (*entity.Order, error) {
+	order, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrOrderNotFound, id)
+	}
+	if version != 0 && version != order.Version {
+		return nil, fmt.Errorf("%w: %s is at version %d, not %d", entity.ErrOrderVersionConflict, id, order.Version, version)
+	}
+	return order, nil
+}
+
+// saveOrder applies the writable fields of replacement to existingOrder, enforcing the status
+// transitions, and writes it at the version it was read. Items are repriced only when they change.
+func (s *OrderService) saveOrder(ctx context.Context, existingOrder, replacement *entity.Order, actor, reason string) error {
+	if replacement.CustomerID == existingOrder.CustomerID {
+		return s.writeOrder(ctx, existingOrder, replacement, actor, reason)
+	}
+
+	// The new customer is read FOR SHARE, so its details cannot change before the order commits
+	return s.inTransaction(ctx, func(ctx context.Context) error {
+		if err := s.assignCustomer(ctx, existingOrder, replacement.CustomerID); err != nil {
+			return err
+		}
+		return s.writeOrder(ctx, existingOrder, replacement, actor, reason)
+	})
+}
+
+// writeOrder applies the writable fields of replacement other than the customer to existingOrder
+// and writes it, for saveOrder
+func (s *OrderService) writeOrder(ctx context.Context, existingOrder, replacement *entity.Order, actor, reason string) error {
+	if replacement.OrderID != existingOrder.OrderID {
+		if !s.allowClientIDs {
+			return entity.NewFieldError(entity.ErrInvalidOrder, "orderId", "is assigned by the server")
+		}
+		existingOrder.OrderID = replacement.OrderID
+	}
+	var change *entity.OrderStatusChange
+	if replacement.Status != existingOrder.Status {
+		if !entity.IsValidOrderStatus(replacement.Status) {
+			return entity.NewFieldError(entity.ErrInvalidOrderStatus, "status", fmt.Sprintf("%q is not an order status", replacement.Status))
+		}
+		if !entity.CanTransitionOrderStatus(existingOrder.Status, replacement.Status) {
+			return fmt.Errorf("%w: %s to %s", entity.ErrInvalidStatusTransition, existingOrder.Status, replacement.Status)
+		}
+		previousStatus := existingOrder.Status
+		existingOrder.Status = replacement.Status
+		change = newStatusChange(existingOrder, previousStatus, actor, reason)
+	}
+	if !sameItems(existingOrder.Items, replacement.Items) {
+		if err := priceOrder(existingOrder, replacement.Items); err != nil {
+			return err
+		}
 	}
 
 	// Update timestamp
 	existingOrder.UpdatedAt = time.Now()
+	event := newOrderEvent(entity.OrderEventType.Updated, existingOrder, actor)
+	if change != nil {
+		change.ChangedAt = existingOrder.UpdatedAt
+		event.Type = entity.OrderEventType.StatusChanged
+		event.FromStatus = change.FromStatus
+		event.ToStatus = change.ToStatus
+		event.Reason = change.Reason
+	}
+	// The repository writes the row only at its current version and increments it
+	event.Order.Version++
 
-	return s.orderRepo.Update(ctx, existingOrder)
+	if err := s.orderRepo.Update(ctx, existingOrder, change, []entity.OrderEvent{event}); err != nil {
+		return err
+	}
+	s.publish(ctx, event)
+	return nil
 }
 
-// DeleteOrder deletes an order by its ID
-func (s *OrderService) DeleteOrder(ctx context.Context, id string) error {
+// DeleteOrder deletes an order by its ID on behalf of actor
+func (s *OrderService) DeleteOrder(ctx context.Context, id, actor string) error {
 	// Check if order exists
 	existingOrder, err := s.orderRepo.FindByID(ctx, id)
 	if err != nil {
 		return err
 	}
 	if existingOrder == nil {
-		return errors.New("order not found")
+		return fmt.Errorf("%w: %s", entity.ErrOrderNotFound, id)
+	}
+
+	now := time.Now()
+	existingOrder.UpdatedAt = now
+	existingOrder.DeletedAt = &now
+	event := newOrderEvent(entity.OrderEventType.Deleted, existingOrder, actor)
+	event.Order.Version++
+	if err := s.orderRepo.Delete(ctx, existingOrder, []entity.OrderEvent{event}); err != nil {
+		return err
+	}
+
+	s.publish(ctx, event)
+	return nil
+}
+
+// GetDeletedOrders retrieves all soft-deleted orders, most recently deleted first
+func (s *OrderService) GetDeletedOrders(ctx context.Context) ([]entity.Order, error) {
+	return s.orderRepo.FindDeleted(ctx)
+}
+
+// RestoreOrder undeletes a soft-deleted order on behalf of actor
+func (s *OrderService) RestoreOrder(ctx context.Context, id, actor string) (*entity.Order, error) {
+	deletedOrder, err := s.findDeletedOrder(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	deletedOrder.UpdatedAt = time.Now()
+	deletedOrder.DeletedAt = nil
+	event := newOrderEvent(entity.OrderEventType.Restored, deletedOrder, actor)
+	event.Order.Version++
+	if err := s.orderRepo.Restore(ctx, deletedOrder, []entity.OrderEvent{event}); err != nil {
+		return nil, err
+	}
+	s.publish(ctx, event)
+	return deletedOrder, nil
+}
+
+// PurgeOrder permanently removes a soft-deleted order on behalf of actor
+func (s *OrderService) PurgeOrder(ctx context.Context, id, actor string) error {
+	deletedOrder, err := s.findDeletedOrder(ctx, id)
+	if err != nil {
+		return err
+	}
+	return s.purge(ctx, deletedOrder, actor)
+}
+
+// PurgeDeletedOrders permanently removes orders soft-deleted before the given time and
+// returns how many were purged
+func (s *OrderService) PurgeDeletedOrders(ctx context.Context, before time.Time) (int, error) {
+	purged := 0
+	for {
+		orders, err := s.orderRepo.FindDeletedBefore(ctx, before, purgeBatchSize)
+		if err != nil {
+			return purged, err
+		}
+		for i := range orders {
+			if err := s.purge(ctx, &orders[i], purgeActor); err != nil {
+				return purged, err
+			}
+			purged++
+		}
+		if len(orders) < purgeBatchSize {
+			return purged, nil
+		}
+	}
+}
+
+// RunPurge purges orders deleted longer than retention ago every interval until ctx is cancelled
+func (s *OrderService) RunPurge(ctx context.Context, interval, retention time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			purged, err := s.PurgeDeletedOrders(ctx, time.Now().Add(-retention))
+			if err != nil {
+				log.Printf("Failed to purge deleted orders: %v", err)
+			}
+			if purged > 0 {
+				log.Printf("Purged %d orders deleted more than %s ago", purged, retention)
+			}
+		}
+	}
+}
+
+// BulkOrders applies a batch of create, update and delete operations on behalf of actor.
+// When atomic, all operations run in one transaction that is rolled back at the first failure,
+// and their events are published once it commits. Otherwise each operation is applied on its
+// own. There is one result per operation, in order.
+func (s *OrderService) BulkOrders(ctx context.Context, operations []entity.BulkOrderOperation, actor string, atomic bool) []entity.BulkOrderResult {
+	results := make([]entity.BulkOrderResult, len(operations))
+	for i, operation := range operations {
+		results[i] = entity.BulkOrderResult{Op: operation.Op, ID: operation.ID}
+	}
+	if !atomic {
+		for i, operation := range operations {
+			results[i].Order, results[i].Err = s.applyBulkOperation(ctx, operation, actor)
+		}
+		return results
+	}
+
+	pending := &pendingEvents{}
+	failed := -1
+	err := s.transactor.InTransaction(context.WithValue(ctx, pendingEventsKey{}, pending), func(ctx context.Context) error {
+		for i, operation := range operations {
+			results[i].Order, results[i].Err = s.applyBulkOperation(ctx, operation, actor)
+			if results[i].Err != nil {
+				failed = i
+				return results[i].Err
+			}
+		}
+		return nil
+	})
+	if err == nil {
+		s.dispatcher.Publish(ctx, pending.events...)
+		return results
+	}
+
+	// Nothing was written; explain why each operation did not take effect
+	for i := range results {
+		switch {
+		case failed < 0:
+			results[i].Err = err
+		case i < failed:
+			results[i].Order, results[i].Err = nil, entity.ErrBulkRolledBack
+		case i > failed:
+			results[i].Err = entity.ErrBulkSkipped
+		}
+	}
+	return results
+}
+
+// applyBulkOperation applies one bulk operation and returns the written order
+func (s *OrderService) applyBulkOperation(ctx context.Context, operation entity.BulkOrderOperation, actor string) (*entity.Order, error) {
+	switch operation.Op {
+	case entity.BulkOrderOp.Create:
+		if operation.Order == nil {
+			return nil, fmt.Errorf("%w: create needs an order", entity.ErrInvalidBulkOperation)
+		}
+		order := *operation.Order
+		if err := s.CreateOrder(ctx, &order, actor); err != nil {
+			return nil, err
+		}
+		return &order, nil
+	case entity.BulkOrderOp.Update:
+		if operation.ID == "" || operation.Order == nil {
+			return nil, fmt.Errorf("%w: update needs an id and an order", entity.ErrInvalidBulkOperation)
+		}
+		order := *operation.Order
+		order.ID = operation.ID
+		if err := s.ReplaceOrder(ctx, &order, actor, operation.Reason); err != nil {
+			return nil, err
+		}
+		return &order, nil
+	case entity.BulkOrderOp.Delete:
+		if operation.ID == "" {
+			return nil, fmt.Errorf("%w: delete needs an id", entity.ErrInvalidBulkOperation)
+		}
+		return nil, s.DeleteOrder(ctx, operation.ID, actor)
+	default:
+		return nil, fmt.Errorf("%w: unknown op %q", entity.ErrInvalidBulkOperation, operation.Op)
+	}
+}
+
+// pendingEventsKey is the context key of the events held back until a bulk transaction commits
+type pendingEventsKey struct{}
+
+// pendingEvents collects the events of a bulk transaction
+type pendingEvents struct {
+	events []entity.OrderEvent
+}
+
+// publish publishes committed events to the dispatcher, or holds them back when ctx belongs
+// to a bulk transaction that has not committed yet
+func (s *OrderService) publish(ctx context.Context, events ...entity.OrderEvent) {
+	if pending, ok := ctx.Value(pendingEventsKey{}).(*pendingEvents); ok {
+		pending.events = append(pending.events, events...)
+		return
+	}
+	s.dispatcher.Publish(ctx, events...)
+}
+
+// inTransaction calls fn in a transaction and publishes the events it publishes once the
+// transaction commits. Within an atomic bulk request they are left to the bulk request.
+func (s *OrderService) inTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	if _, ok := ctx.Value(pendingEventsKey{}).(*pendingEvents); ok {
+		return s.transactor.InTransaction(ctx, fn)
+	}
+
+	pending := &pendingEvents{}
+	if err := s.transactor.InTransaction(context.WithValue(ctx, pendingEventsKey{}, pending), fn); err != nil {
+		return err
+	}
+	s.dispatcher.Publish(ctx, pending.events...)
+	return nil
+}
+
+// findDeletedOrder retrieves a soft-deleted order, telling apart missing and live orders
+func (s *OrderService) findDeletedOrder(ctx context.Context, id string) (*entity.Order, error) {
+	deletedOrder, err := s.orderRepo.FindDeletedByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if deletedOrder != nil {
+		return deletedOrder, nil
+	}
+
+	liveOrder, err := s.orderRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if liveOrder != nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrOrderNotDeleted, id)
+	}
+	return nil, fmt.Errorf("%w: %s", entity.ErrOrderNotFound, id)
+}
+
+// purge permanently removes a soft-deleted order
+func (s *OrderService) purge(ctx context.Context, order *entity.Order, actor string) error {
+	event := newOrderEvent(entity.OrderEventType.Purged, order, actor)
+	event.OccurredAt = time.Now()
+	if err := s.orderRepo.Purge(ctx, order.ID, []entity.OrderEvent{event}); err != nil {
+		return err
+	}
+
+	s.publish(ctx, event)
+	return nil
+}
+
+// newOrderEvent creates an event of the given type carrying a copy of the order
+func newOrderEvent(eventType string, order *entity.Order, actor string) entity.OrderEvent {
+	snapshot := *order
+	snapshot.Items = append([]entity.OrderItem(nil), order.Items...)
+	return entity.OrderEvent{
+		ID:         uuid.NewString(),
+		Type:       eventType,
+		OrderID:    order.ID,
+		Actor:      actor,
+		OccurredAt: order.UpdatedAt,
+		Order:      snapshot,
+	}
+}
+
+// assignIdentifiers generates the id and orderId of a new order. Client-supplied values are
+// kept when allowed and rejected otherwise.
+func (s *OrderService) assignIdentifiers(ctx context.Context, order *entity.Order) error {
+	if !s.allowClientIDs && (order.ID != "" || order.OrderID != "") {
+		return &entity.ValidationError{Err: entity.ErrInvalidOrder, Fields: []entity.FieldError{
+			{Field: "id", Message: "is assigned by the server"},
+			{Field: "orderId", Message: "is assigned by the server"},
+		}}
+	}
+
+	if order.ID == "" {
+		id, err := uuid.NewV7()
+		if err != nil {
+			return err
+		}
+		order.ID = id.String()
+	}
+	if order.OrderID == "" {
+		number, err := s.orderRepo.NextOrderNumber(ctx)
+		if err != nil {
+			return err
+		}
+		order.OrderID = fmt.Sprintf("ORD-%d-%06d", order.CreatedAt.UTC().Year(), number)
+	}
+	return nil
+}
+
+// newStatusChange records an order moving from one status to its current status
+func newStatusChange(order *entity.Order, from, actor, reason string) *entity.OrderStatusChange {
+	return &entity.OrderStatusChange{
+		ID:         uuid.NewString(),
+		OrderID:    order.ID,
+		FromStatus: from,
+		ToStatus:   order.Status,
+		Actor:      actor,
+		Reason:     reason,
+		ChangedAt:  order.UpdatedAt,
+	}
+}
+
+// assignCustomer points an order at a customer and copies its name and email. The customer is
+// locked against updates until the transaction carried by ctx ends.
+func (s *OrderService) assignCustomer(ctx context.Context, order *entity.Order, customerID string) error {
+	customer, err := s.customerRepo.FindByIDForShare(ctx, customerID)
+	if err != nil {
+		return err
+	}
+	if customer == nil {
+		return entity.NewFieldError(entity.ErrCustomerNotFound, "customerId", fmt.Sprintf("%s does not exist", customerID))
+	}
+
+	order.CustomerID = customer.ID
+	order.CustomerName = customer.Name
+	order.CustomerEmail = customer.Email
+	return nil
+}
+
+// sameItems reports whether items has the same SKUs, quantities and unit prices as current,
+// in the same order, so an unchanged replacement keeps the item IDs
+func sameItems(current, items []entity.OrderItem) bool {
+	if len(current) != len(items) {
+		return false
+	}
+	for i, item := range items {
+		if strings.TrimSpace(item.SKU) != current[i].SKU ||
+			item.Quantity != current[i].Quantity ||
+			item.UnitPrice != current[i].UnitPrice {
+			return false
+		}
+	}
+	return true
+}
+
+// priceOrder replaces the items of an order, assigning new item IDs, and computes
+// the line totals and the order total. All items must share one currency.
+func priceOrder(order *entity.Order, items []entity.OrderItem) error {
+	order.Items = make([]entity.OrderItem, len(items))
+	order.Total = entity.Money{}
+	if len(items) == 0 {
+		return nil
+	}
+
+	total, err := entity.NewMoney(0, items[0].UnitPrice.Currency)
+	if err != nil {
+		return err
+	}
+	for i, item := range items {
+		item.SKU = strings.TrimSpace(item.SKU)
+		if item.SKU == "" {
+			return entity.NewFieldError(entity.ErrInvalidOrderItem, fmt.Sprintf("items[%d].sku", i), "is required")
+		}
+		if item.Quantity <= 0 {
+			return entity.NewFieldError(entity.ErrInvalidOrderItem, fmt.Sprintf("items[%d].quantity", i), "must be positive")
+		}
+		if item.UnitPrice.Amount < 0 {
+			return entity.NewFieldError(entity.ErrInvalidOrderItem, fmt.Sprintf("items[%d].unitPrice.amount", i), "must not be negative")
+		}
+
+		item.ID = uuid.NewString()
+		if item.LineTotal, err = item.UnitPrice.Multiply(int64(item.Quantity)); err != nil {
+			return err
+		}
+		if total, err = total.Add(item.LineTotal); err != nil {
+			return err
+		}
+		order.Items[i] = item
 	}
+	order.Total = total
 
-	return s.orderRepo.Delete(ctx, id)
+	return nil
 }
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// versionedOrderRepository keeps orders in memory and writes them like the GORM repository,
+// only while the stored row is at the version on the entity. Methods the tests do not use
+// are left to the embedded interface and panic.
+type versionedOrderRepository struct {
+	repository.OrderRepository
+	orders map[string]entity.Order
+	outbox []entity.OrderEvent
+	// beforeWrite runs before each write, to simulate a concurrent writer
+	beforeWrite func()
+}
+
+func (r *versionedOrderRepository) FindByID(_ context.Context, id string) (*entity.Order, error) {
+	order, ok := r.orders[id]
+	if !ok || order.DeletedAt != nil {
+		return nil, nil
+	}
+	return &order, nil
+}
+
+func (r *versionedOrderRepository) Update(_ context.Context, order *entity.Order, _ *entity.OrderStatusChange, events []entity.OrderEvent) error {
+	if err := r.write(order); err != nil {
+		return err
+	}
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+func (r *versionedOrderRepository) Delete(_ context.Context, order *entity.Order, events []entity.OrderEvent) error {
+	if err := r.write(order); err != nil {
+		return err
+	}
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+// write stores order at the next version when the stored order is still at its version
+func (r *versionedOrderRepository) write(order *entity.Order) error {
+	if r.beforeWrite != nil {
+		r.beforeWrite()
+	}
+	if r.orders[order.ID].Version != order.Version {
+		return fmt.Errorf("%w: %s is no longer at version %d", entity.ErrOrderVersionConflict, order.ID, order.Version)
+	}
+	order.Version++
+	r.orders[order.ID] = *order
+	return nil
+}
+
+// bumpVersion simulates another writer changing the order
+func (r *versionedOrderRepository) bumpVersion(id string) {
+	order := r.orders[id]
+	order.Version++
+	r.orders[id] = order
+}
+
+func newVersionedOrderService(t *testing.T) (*OrderService, *versionedOrderRepository, *event.Recorder) {
+	t.Helper()
+	orderRepo := &versionedOrderRepository{orders: map[string]entity.Order{
+		"order-1": {ID: "order-1", OrderID: "1001", CustomerID: "customer-1", Status: entity.OrderStatus.New, Version: 3},
+	}}
+	dispatcher := event.NewDispatcher()
+	recorder := event.NewRecorder(dispatcher)
+	return NewOrderService(orderRepo, nil, nil, nil, dispatcher, false), orderRepo, recorder
+}
+
+func TestOrderServiceVersionConflicts(t *testing.T) {
+	tests := []struct {
+		name string
+		// version is the version the caller read, zero to skip the check
+		version int64
+		// concurrent reports whether another writer changes the order between read and write
+		concurrent  bool
+		wantErr     error
+		wantVersion int64
+	}{
+		{name: "current version", version: 3, wantVersion: 4},
+		{name: "no version", version: 0, wantVersion: 4},
+		{name: "stale version", version: 2, wantErr: entity.ErrOrderVersionConflict, wantVersion: 3},
+		{name: "future version", version: 4, wantErr: entity.ErrOrderVersionConflict, wantVersion: 3},
+		{name: "changed after read", version: 3, concurrent: true, wantErr: entity.ErrOrderVersionConflict, wantVersion: 4},
+		{name: "changed after read without version", version: 0, concurrent: true, wantErr: entity.ErrOrderVersionConflict, wantVersion: 4},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderService, orderRepo, recorder := newVersionedOrderService(t)
+			if tt.concurrent {
+				orderRepo.beforeWrite = func() { orderRepo.bumpVersion("order-1") }
+			}
+
+			order := &entity.Order{ID: "order-1", Status: entity.OrderStatus.Processing, Version: tt.version}
+			err := orderService.UpdateOrder(context.Background(), order, "tester", "")
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, entity.ErrConflict) {
+					t.Fatalf("UpdateOrder() error = %v, want %v", err, tt.wantErr)
+				}
+				if len(orderRepo.outbox) != 0 || len(recorder.Events()) != 0 {
+					t.Errorf("a conflicting update wrote %d and published %d events", len(orderRepo.outbox), len(recorder.Events()))
+				}
+				if status := orderRepo.orders["order-1"].Status; status != entity.OrderStatus.New {
+					t.Errorf("a conflicting update changed the status to %s", status)
+				}
+			} else {
+				if err != nil {
+					t.Fatalf("UpdateOrder() error = %v", err)
+				}
+				if order.Version != tt.wantVersion || order.Status != entity.OrderStatus.Processing {
+					t.Errorf("UpdateOrder() set version %d and status %s, want %d and PROCESSING", order.Version, order.Status, tt.wantVersion)
+				}
+				events := recorder.EventsOfType(entity.OrderEventType.StatusChanged)
+				if len(events) != 1 || events[0].Order.Version != tt.wantVersion {
+					t.Fatalf("published %+v, want one status change at version %d", recorder.Events(), tt.wantVersion)
+				}
+			}
+			if got := orderRepo.orders["order-1"].Version; got != tt.wantVersion {
+				t.Errorf("stored version = %d, want %d", got, tt.wantVersion)
+			}
+		})
+	}
+}
+
+func TestDeleteOrderVersionConflict(t *testing.T) {
+	orderService, orderRepo, recorder := newVersionedOrderService(t)
+	orderRepo.beforeWrite = func() { orderRepo.bumpVersion("order-1") }
+
+	err := orderService.DeleteOrder(context.Background(), "order-1", "tester")
+	if !errors.Is(err, entity.ErrOrderVersionConflict) {
+		t.Fatalf("DeleteOrder() error = %v, want ErrOrderVersionConflict", err)
+	}
+	if orderRepo.orders["order-1"].DeletedAt != nil {
+		t.Error("a conflicting delete deleted the order")
+	}
+	if len(recorder.Events()) != 0 {
+		t.Errorf("a conflicting delete published %+v", recorder.Events())
+	}
+}
+
+func (r *versionedOrderRepository) NextOrderNumber(context.Context) (int64, error) {
+	return int64(len(r.orders) + 1), nil
+}
+
+func (r *versionedOrderRepository) Create(_ context.Context, order *entity.Order, _ *entity.OrderStatusChange, events []entity.OrderEvent) error {
+	if _, ok := r.orders[order.ID]; ok {
+		return fmt.Errorf("%w: id %s is already used", entity.ErrDuplicateOrder, order.ID)
+	}
+	r.orders[order.ID] = *order
+	r.outbox = append(r.outbox, events...)
+	return nil
+}
+
+// inTransactionKey marks the context of a transaction started by recordingTransactor
+type inTransactionKey struct{}
+
+// recordingTransactor runs fn with a context marked as in a transaction, and records how many
+// events were published when each transaction ended
+type recordingTransactor struct {
+	recorder       *event.Recorder
+	publishedAtEnd []int
+}
+
+func (t *recordingTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	err := fn(context.WithValue(ctx, inTransactionKey{}, true))
+	t.publishedAtEnd = append(t.publishedAtEnd, len(t.recorder.Events()))
+	return err
+}
+
+// sharingCustomerRepository keeps customers in memory and records the IDs of those read
+// FOR SHARE inside a transaction
+type sharingCustomerRepository struct {
+	repository.CustomerRepository
+	customers map[string]entity.Customer
+	shared    []string
+}
+
+func (r *sharingCustomerRepository) FindByIDForShare(ctx context.Context, id string) (*entity.Customer, error) {
+	if inTransaction, _ := ctx.Value(inTransactionKey{}).(bool); inTransaction {
+		r.shared = append(r.shared, id)
+	}
+	customer, ok := r.customers[id]
+	if !ok {
+		return nil, nil
+	}
+	return &customer, nil
+}
+
+func TestOrderServiceLocksTheAssignedCustomer(t *testing.T) {
+	tests := []struct {
+		name       string
+		customerID string
+		// create creates an order rather than moving order-1 to the customer
+		create  bool
+		wantErr error
+	}{
+		{name: "create", customerID: "customer-2", create: true},
+		{name: "create for a missing customer", customerID: "customer-3", create: true, wantErr: entity.ErrCustomerNotFound},
+		{name: "reassign", customerID: "customer-2"},
+		{name: "reassign to a missing customer", customerID: "customer-3", wantErr: entity.ErrCustomerNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orderRepo := &versionedOrderRepository{orders: map[string]entity.Order{
+				"order-1": {ID: "order-1", OrderID: "1001", CustomerID: "customer-1", Status: entity.OrderStatus.New, Version: 3},
+			}}
+			customerRepo := &sharingCustomerRepository{customers: map[string]entity.Customer{
+				"customer-1": {ID: "customer-1", Name: "Ada", Email: "ada@example.com"},
+				"customer-2": {ID: "customer-2", Name: "Grace", Email: "grace@example.com"},
+			}}
+			dispatcher := event.NewDispatcher()
+			recorder := event.NewRecorder(dispatcher)
+			transactor := &recordingTransactor{recorder: recorder}
+			orderService := NewOrderService(orderRepo, nil, customerRepo, transactor, dispatcher, false)
+
+			order := &entity.Order{CustomerID: tt.customerID}
+			var err error
+			if tt.create {
+				err = orderService.CreateOrder(context.Background(), order, "tester")
+			} else {
+				order.ID = "order-1"
+				err = orderService.UpdateOrder(context.Background(), order, "tester", "")
+			}
+
+			if len(customerRepo.shared) != 1 || customerRepo.shared[0] != tt.customerID {
+				t.Errorf("customers read FOR SHARE in a transaction = %v, want [%s]", customerRepo.shared, tt.customerID)
+			}
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) {
+					t.Fatalf("error = %v, want %v", err, tt.wantErr)
+				}
+				if len(orderRepo.outbox) != 0 || len(recorder.Events()) != 0 {
+					t.Errorf("a failed write wrote %d and published %d events", len(orderRepo.outbox), len(recorder.Events()))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if order.CustomerName != "Grace" || order.CustomerEmail != "grace@example.com" {
+				t.Errorf("order customer = %s <%s>, want the details of %s", order.CustomerName, order.CustomerEmail, tt.customerID)
+			}
+			if len(transactor.publishedAtEnd) != 1 || transactor.publishedAtEnd[0] != 0 || len(recorder.Events()) != 1 {
+				t.Errorf("published %d events, %v when the transaction ended, want 1 after it", len(recorder.Events()), transactor.publishedAtEnd)
+			}
+		})
+	}
+}
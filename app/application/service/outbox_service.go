@@ -0,0 +1,52 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// OutboxService maintains the event outbox. Events stay in the table for the retention
+// period after the outbox event router has read them from the WAL, so they can be inspected
+// and replayed.
+type OutboxService struct {
+	outboxRepo repository.OutboxRepository
+	retention  time.Duration
+}
+
+// NewOutboxService creates a new OutboxService
+func NewOutboxService(outboxRepo repository.OutboxRepository, retention time.Duration) *OutboxService {
+	return &OutboxService{
+		outboxRepo: outboxRepo,
+		retention:  retention,
+	}
+}
+
+// Prune deletes events older than the retention period
+func (s *OutboxService) Prune(ctx context.Context) (int64, error) {
+	return s.outboxRepo.DeleteBefore(ctx, time.Now().Add(-s.retention))
+}
+
+// Run prunes the outbox every interval until ctx is cancelled
+func (s *OutboxService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			deleted, err := s.Prune(ctx)
+			if err != nil {
+				log.Printf("Failed to prune outbox: %v", err)
+				continue
+			}
+			if deleted > 0 {
+				log.Printf("Pruned %d outbox events", deleted)
+			}
+		}
+	}
+}
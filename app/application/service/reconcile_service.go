@@ -0,0 +1,293 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// orderDataCollection is the Debezium data collection holding orders
+const orderDataCollection = "public.orders"
+
+var (
+	// ErrReconciliationRunning is returned when a reconciliation is started while another one is in progress
+	ErrReconciliationRunning = entity.NewError(entity.ErrConflict, "reconciliation already running")
+	// ErrReconciliationReportNotFound is returned when a reconciliation report does not exist
+	ErrReconciliationReportNotFound = entity.NewError(entity.ErrNotFound, "reconciliation report not found")
+)
+
+// ReconcileService compares orders in PostgreSQL with their documents in the search index.
+// Missing and stale documents are repaired through an incremental snapshot so the
+// connector stays the only writer of the index; orphaned documents are deleted directly.
+type ReconcileService struct {
+	orderRepo          repository.OrderRepository
+	orderSearchRepo    repository.OrderSearchRepository
+	reconciliationRepo repository.ReconciliationRepository
+	snapshotService    *SnapshotService
+	chunkSize          int
+	maxIDs             int
+	running            atomic.Bool
+}
+
+// NewReconcileService creates a new ReconcileService
+func NewReconcileService(orderRepo repository.OrderRepository, orderSearchRepo repository.OrderSearchRepository,
+	reconciliationRepo repository.ReconciliationRepository, snapshotService *SnapshotService, chunkSize, maxIDs int) *ReconcileService {
+	return &ReconcileService{
+		orderRepo:          orderRepo,
+		orderSearchRepo:    orderSearchRepo,
+		reconciliationRepo: reconciliationRepo,
+		snapshotService:    snapshotService,
+		chunkSize:          chunkSize,
+		maxIDs:             maxIDs,
+	}
+}
+
+// GetAllReports retrieves all reconciliation reports
+func (s *ReconcileService) GetAllReports(ctx context.Context) ([]entity.ReconciliationReport, error) {
+	return s.reconciliationRepo.FindAll(ctx)
+}
+
+// GetReportByID retrieves a reconciliation report by its ID
+func (s *ReconcileService) GetReportByID(ctx context.Context, id string) (*entity.ReconciliationReport, error) {
+	report, err := s.reconciliationRepo.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if report == nil {
+		return nil, fmt.Errorf("%w: %s", ErrReconciliationReportNotFound, id)
+	}
+	return report, nil
+}
+
+// Reconcile runs a reconciliation and returns its finished report
+func (s *ReconcileService) Reconcile(ctx context.Context, repair bool) (*entity.ReconciliationReport, error) {
+	report, err := s.begin(ctx, repair)
+	if err != nil {
+		return nil, err
+	}
+	s.run(ctx, report)
+	return report, nil
+}
+
+// StartReconcile starts a reconciliation in the background and returns its running report
+func (s *ReconcileService) StartReconcile(ctx context.Context, repair bool) (*entity.ReconciliationReport, error) {
+	report, err := s.begin(ctx, repair)
+	if err != nil {
+		return nil, err
+	}
+	started := *report
+	go s.run(context.Background(), report)
+	return &started, nil
+}
+
+// RunSchedule reconciles every interval until ctx is cancelled
+func (s *ReconcileService) RunSchedule(ctx context.Context, interval time.Duration, repair bool) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			report, err := s.Reconcile(ctx, repair)
+			if err != nil {
+				log.Printf("Failed to start scheduled reconciliation: %v", err)
+				continue
+			}
+			log.Printf("Reconciliation %s %s: scanned=%d matching=%d missing=%d stale=%d orphaned=%d repaired=%d",
+				report.ID, report.Status, report.Scanned, report.Matching, report.Missing, report.Stale, report.Orphaned, report.Repaired)
+		}
+	}
+}
+
+// begin claims the reconciler and stores a running report
+func (s *ReconcileService) begin(ctx context.Context, repair bool) (*entity.ReconciliationReport, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, ErrReconciliationRunning
+	}
+
+	report := &entity.ReconciliationReport{
+		ID:        uuid.NewString(),
+		Status:    entity.ReconciliationStatus.Running,
+		Repair:    repair,
+		StartedAt: time.Now(),
+	}
+	if err := s.reconciliationRepo.Create(ctx, report); err != nil {
+		s.running.Store(false)
+		return nil, err
+	}
+	return report, nil
+}
+
+// run performs the reconciliation, stores the final report and releases the reconciler
+func (s *ReconcileService) run(ctx context.Context, report *entity.ReconciliationReport) {
+	defer s.running.Store(false)
+
+	err := s.compareOrders(ctx, report)
+	if err == nil {
+		err = s.findOrphans(ctx, report)
+	}
+
+	finishedAt := time.Now()
+	report.FinishedAt = &finishedAt
+	report.Status = entity.ReconciliationStatus.Completed
+	if err != nil {
+		report.Status = entity.ReconciliationStatus.Failed
+		report.Error = err.Error()
+	}
+
+	if err := s.reconciliationRepo.Update(ctx, report); err != nil {
+		log.Printf("Failed to store reconciliation report %s: %v", report.ID, err)
+	}
+}
+
+// compareOrders walks the orders table in chunks and classifies each row against its document
+func (s *ReconcileService) compareOrders(ctx context.Context, report *entity.ReconciliationReport) error {
+	afterID := ""
+	for {
+		orders, err := s.orderRepo.FindAfterID(ctx, afterID, s.chunkSize)
+		if err != nil {
+			return err
+		}
+		if len(orders) == 0 {
+			return nil
+		}
+
+		ids := make([]string, len(orders))
+		for i, order := range orders {
+			ids[i] = order.ID
+		}
+		indexed, err := s.orderSearchRepo.FindByIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+
+		var drifted []string
+		for i := range orders {
+			order := &orders[i]
+			report.Scanned++
+
+			document, found := indexed[order.ID]
+			switch {
+			case !found:
+				report.Missing++
+				report.MissingIDs = s.appendID(report.MissingIDs, order.ID)
+				drifted = append(drifted, order.ID)
+			case orderChecksum(&document) == orderChecksum(order):
+				report.Matching++
+			case document.UpdatedAt.After(order.UpdatedAt):
+				// The row changed after it was read; the newer document is not drift
+				report.Matching++
+			default:
+				report.Stale++
+				report.StaleIDs = s.appendID(report.StaleIDs, order.ID)
+				drifted = append(drifted, order.ID)
+			}
+		}
+
+		if report.Repair && len(drifted) > 0 {
+			if err := s.reindex(ctx, drifted); err != nil {
+				return err
+			}
+			report.Repaired += len(drifted)
+		}
+
+		afterID = orders[len(orders)-1].ID
+	}
+}
+
+// findOrphans walks the indexed documents and finds those without an orders row
+func (s *ReconcileService) findOrphans(ctx context.Context, report *entity.ReconciliationReport) error {
+	return s.orderSearchRepo.ScanIDs(ctx, s.chunkSize, func(ids []string) error {
+		existing, err := s.orderRepo.FindExistingIDs(ctx, ids)
+		if err != nil {
+			return err
+		}
+		exists := make(map[string]bool, len(existing))
+		for _, id := range existing {
+			exists[id] = true
+		}
+
+		var orphaned []string
+		for _, id := range ids {
+			if !exists[id] {
+				orphaned = append(orphaned, id)
+				report.OrphanedIDs = s.appendID(report.OrphanedIDs, id)
+			}
+		}
+		report.Orphaned += len(orphaned)
+
+		if report.Repair && len(orphaned) > 0 {
+			if err := s.orderSearchRepo.DeleteByIDs(ctx, orphaned); err != nil {
+				return err
+			}
+			report.Repaired += len(orphaned)
+		}
+		return nil
+	})
+}
+
+// reindex requests an incremental snapshot of the given order IDs
+func (s *ReconcileService) reindex(ctx context.Context, ids []string) error {
+	request := &entity.SnapshotRequest{
+		DataCollections: []string{orderDataCollection},
+		Conditions: []entity.SnapshotCondition{{
+			DataCollection: orderDataCollection,
+			Column:         "id",
+			Operator:       entity.SnapshotOperator.In,
+			Values:         ids,
+		}},
+	}
+	return s.snapshotService.RequestSnapshot(ctx, request)
+}
+
+// appendID appends id unless the report already lists the configured maximum
+func (s *ReconcileService) appendID(ids []string, id string) []string {
+	if len(ids) >= s.maxIDs {
+		return ids
+	}
+	return append(ids, id)
+}
+
+// orderChecksum hashes the replicated fields of an order, with timestamps at PostgreSQL precision
+func orderChecksum(order *entity.Order) string {
+	formatTime := func(t time.Time) string {
+		if t.IsZero() {
+			return ""
+		}
+		return t.UTC().Truncate(time.Microsecond).Format(time.RFC3339Nano)
+	}
+	deletedAt := ""
+	if order.DeletedAt != nil {
+		deletedAt = formatTime(*order.DeletedAt)
+	}
+
+	fields := []string{
+		order.ID,
+		order.OrderID,
+		order.CustomerID,
+		order.CustomerName,
+		order.CustomerEmail,
+		order.Status,
+		formatTime(order.CreatedAt),
+		formatTime(order.UpdatedAt),
+		deletedAt,
+		fmt.Sprintf("%d %s", order.Total.Amount, order.Total.Currency),
+		fmt.Sprintf("%d", order.Version),
+	}
+	for _, item := range order.Items {
+		fields = append(fields, item.ID)
+	}
+	sum := sha256.Sum256([]byte(strings.Join(fields, "\x1f")))
+	return hex.EncodeToString(sum[:])
+}
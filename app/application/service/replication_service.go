@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"log"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+var (
+	// ErrSlotNotFound is returned when a replication slot does not exist
+	ErrSlotNotFound = entity.NewError(entity.ErrNotFound, "replication slot not found")
+	// ErrSlotActive is returned when dropping a slot that a consumer is still connected to
+	ErrSlotActive = entity.NewError(entity.ErrConflict, "replication slot is active")
+	// ErrSlotDropDisabled is returned when dropping slots is not enabled in the configuration
+	ErrSlotDropDisabled = entity.NewError(entity.ErrConflict, "dropping replication slots is disabled")
+	// ErrSlotDropNotConfirmed is returned when the drop confirmation does not repeat the slot name
+	ErrSlotDropNotConfirmed = entity.NewError(entity.ErrValidation, "slot drop must be confirmed with the slot name")
+)
+
+// ReplicationService inspects replication slots and raises alerts for inactive slots
+// retaining more WAL than allowed
+type ReplicationService struct {
+	replicationRepo  repository.ReplicationRepository
+	maxRetainedBytes int64
+	allowDrop        bool
+}
+
+// NewReplicationService creates a new ReplicationService
+func NewReplicationService(replicationRepo repository.ReplicationRepository, maxRetainedBytes int64, allowDrop bool) *ReplicationService {
+	return &ReplicationService{
+		replicationRepo:  replicationRepo,
+		maxRetainedBytes: maxRetainedBytes,
+		allowDrop:        allowDrop,
+	}
+}
+
+// GetSlots retrieves all replication slots with their alert flag set
+func (s *ReplicationService) GetSlots(ctx context.Context) ([]entity.ReplicationSlot, error) {
+	slots, err := s.replicationRepo.FindSlots(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for i := range slots {
+		slots[i].Alert = s.isAlerting(&slots[i])
+	}
+	return slots, nil
+}
+
+// CheckSlots logs a warning for every alerting slot
+func (s *ReplicationService) CheckSlots(ctx context.Context) error {
+	slots, err := s.GetSlots(ctx)
+	if err != nil {
+		return err
+	}
+	for _, slot := range slots {
+		if slot.Alert {
+			log.Printf("WARNING: replication slot %s is inactive and retains %d MB of WAL (wal_status=%s)",
+				slot.Name, slot.RetainedWALBytes/(1024*1024), slot.WALStatus)
+		}
+	}
+	return nil
+}
+
+// Run checks the replication slots every interval until ctx is cancelled
+func (s *ReplicationService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.CheckSlots(ctx); err != nil {
+				log.Printf("Failed to check replication slots: %v", err)
+			}
+		}
+	}
+}
+
+// DropSlot drops an inactive replication slot. Dropping must be enabled in the
+// configuration and confirmed by repeating the slot name.
+func (s *ReplicationService) DropSlot(ctx context.Context, name, confirm string) error {
+	if !s.allowDrop {
+		return ErrSlotDropDisabled
+	}
+	if confirm != name {
+		return ErrSlotDropNotConfirmed
+	}
+
+	slot, err := s.replicationRepo.FindSlotByName(ctx, name)
+	if err != nil {
+		return err
+	}
+	if slot == nil {
+		return ErrSlotNotFound
+	}
+	if slot.Active {
+		return ErrSlotActive
+	}
+
+	log.Printf("Dropping replication slot %s retaining %d bytes of WAL", slot.Name, slot.RetainedWALBytes)
+	return s.replicationRepo.DropSlot(ctx, name)
+}
+
+// isAlerting reports whether a slot is inactive and retains too much WAL, or has lost WAL
+func (s *ReplicationService) isAlerting(slot *entity.ReplicationSlot) bool {
+	if slot.WALStatus == "lost" {
+		return true
+	}
+	return !slot.Active && slot.RetainedWALBytes > s.maxRetainedBytes
+}
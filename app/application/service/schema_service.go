@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// ErrIncompatibleSchema is returned when a source table can no longer be indexed into its mapping
+var ErrIncompatibleSchema = entity.NewError(entity.ErrConflict, "source table is incompatible with the search mapping")
+
+// fieldTypes maps PostgreSQL data types to the mapping type for the value Debezium emits
+// and the mapping types that can hold it. JSON columns hold arrays of child records; Debezium
+// emits them as strings, which the index ingest pipeline parses into nested documents.
+var fieldTypes = map[string]struct {
+	Desired    string
+	Compatible []string
+}{
+	"character varying":           {"text", []string{"text", "keyword", "wildcard", "match_only_text"}},
+	"character":                   {"text", []string{"text", "keyword", "wildcard", "match_only_text"}},
+	"text":                        {"text", []string{"text", "keyword", "wildcard", "match_only_text"}},
+	"uuid":                        {"keyword", []string{"keyword", "text"}},
+	"json":                        {"nested", []string{"nested"}},
+	"jsonb":                       {"nested", []string{"nested"}},
+	"numeric":                     {"keyword", []string{"keyword", "text"}},
+	"smallint":                    {"integer", []string{"short", "integer", "long"}},
+	"integer":                     {"integer", []string{"integer", "long"}},
+	"bigint":                      {"long", []string{"long"}},
+	"real":                        {"float", []string{"float", "double"}},
+	"double precision":            {"double", []string{"double"}},
+	"boolean":                     {"boolean", []string{"boolean"}},
+	"date":                        {"integer", []string{"integer", "long"}},
+	"timestamp with time zone":    {"date", []string{"date", "date_nanos"}},
+	"timestamp without time zone": {"long", []string{"long"}},
+}
+
+// debeziumFields are added to every document by the ExtractNewRecordState transform
+var debeziumFields = map[string]string{
+	"__deleted": "keyword",
+}
+
+// SchemaService detects changes between a source table and the search mapping it is replicated into
+type SchemaService struct {
+	schemaRepo  repository.SchemaRepository
+	mappingRepo repository.MappingRepository
+}
+
+// NewSchemaService creates a new SchemaService
+func NewSchemaService(schemaRepo repository.SchemaRepository, mappingRepo repository.MappingRepository) *SchemaService {
+	return &SchemaService{
+		schemaRepo:  schemaRepo,
+		mappingRepo: mappingRepo,
+	}
+}
+
+// Plan diffs the table columns against the index mapping. New columns are additive-safe,
+// columns whose type no longer fits the mapped field require a reindex, and mapped fields
+// without a column are reported as removed.
+func (s *SchemaService) Plan(ctx context.Context, table, index string) (*entity.SchemaPlan, error) {
+	columns, err := s.schemaRepo.FindColumns(ctx, table)
+	if err != nil {
+		return nil, err
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("table %s has no columns", table)
+	}
+
+	mapped, exists, err := s.mappingRepo.FindFieldTypes(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+
+	plan := &entity.SchemaPlan{Table: table, Index: index, IndexExists: exists}
+	seen := make(map[string]bool, len(columns))
+
+	for _, column := range columns {
+		seen[column.Name] = true
+		if column.DataType == "json" || column.DataType == "jsonb" {
+			plan.JSONFields = append(plan.JSONFields, column.Name)
+		}
+		fieldType, known := fieldTypes[column.DataType]
+		mappingType, isMapped := mapped[column.Name]
+
+		switch {
+		case !known:
+			plan.Changes = append(plan.Changes, entity.SchemaChange{
+				Field:       column.Name,
+				ColumnType:  column.DataType,
+				MappingType: mappingType,
+				Kind:        entity.SchemaChangeKind.Unknown,
+				Detail:      "column type is not managed; the field is left to dynamic mapping",
+			})
+		case !isMapped:
+			plan.Changes = append(plan.Changes, entity.SchemaChange{
+				Field:       column.Name,
+				ColumnType:  column.DataType,
+				MappingType: fieldType.Desired,
+				Kind:        entity.SchemaChangeKind.Additive,
+				Detail:      fmt.Sprintf("add %s field", fieldType.Desired),
+			})
+		case !slices.Contains(fieldType.Compatible, mappingType):
+			plan.NeedsReindex = true
+			plan.Changes = append(plan.Changes, entity.SchemaChange{
+				Field:       column.Name,
+				ColumnType:  column.DataType,
+				MappingType: mappingType,
+				Kind:        entity.SchemaChangeKind.Reindex,
+				Detail:      fmt.Sprintf("mapped as %s but %s needs %s; reindex into a new index", mappingType, column.DataType, fieldType.Desired),
+			})
+		}
+	}
+
+	for field, fieldType := range debeziumFields {
+		seen[field] = true
+		if _, isMapped := mapped[field]; !isMapped {
+			plan.Changes = append(plan.Changes, entity.SchemaChange{
+				Field:       field,
+				MappingType: fieldType,
+				Kind:        entity.SchemaChangeKind.Additive,
+				Detail:      fmt.Sprintf("add %s field", fieldType),
+			})
+		}
+	}
+
+	for field, mappingType := range mapped {
+		if !seen[field] {
+			plan.Changes = append(plan.Changes, entity.SchemaChange{
+				Field:       field,
+				MappingType: mappingType,
+				Kind:        entity.SchemaChangeKind.Removed,
+				Detail:      "column no longer exists; the field stays in the mapping",
+			})
+		}
+	}
+
+	sort.SliceStable(plan.Changes, func(i, j int) bool { return plan.Changes[i].Field < plan.Changes[j].Field })
+	return plan, nil
+}
+
+// Apply creates the index or adds the additive fields of the plan, and points the index at an
+// ingest pipeline parsing its JSON fields. Plans that need a reindex are refused.
+func (s *SchemaService) Apply(ctx context.Context, plan *entity.SchemaPlan) error {
+	if plan.NeedsReindex {
+		return fmt.Errorf("%w: %s", ErrIncompatibleSchema, describePlan(plan))
+	}
+
+	additive := make(map[string]string)
+	for _, change := range plan.Changes {
+		if change.Kind == entity.SchemaChangeKind.Additive {
+			additive[change.Field] = change.MappingType
+		}
+	}
+
+	switch {
+	case !plan.IndexExists:
+		if err := s.mappingRepo.CreateIndex(ctx, plan.Index, additive); err != nil {
+			return err
+		}
+	case len(additive) > 0:
+		if err := s.mappingRepo.AddFields(ctx, plan.Index, additive); err != nil {
+			return err
+		}
+	}
+
+	return s.mappingRepo.EnsureJSONPipeline(ctx, plan.Index, plan.JSONFields)
+}
+
+// Check plans and applies the mapping changes for a table, logging the plan
+func (s *SchemaService) Check(ctx context.Context, table, index string) (*entity.SchemaPlan, error) {
+	plan, err := s.Plan(ctx, table, index)
+	if err != nil {
+		return nil, err
+	}
+	if len(plan.Changes) > 0 {
+		log.Printf("Schema plan for %s -> %s: %s", table, index, describePlan(plan))
+	}
+	return plan, s.Apply(ctx, plan)
+}
+
+// describePlan renders the changes of a plan on one line
+func describePlan(plan *entity.SchemaPlan) string {
+	parts := make([]string, len(plan.Changes))
+	for i, change := range plan.Changes {
+		parts[i] = fmt.Sprintf("%s %s (%s)", change.Kind, change.Field, change.Detail)
+	}
+	return strings.Join(parts, "; ")
+}
@@ -0,0 +1,316 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"regexp"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+var (
+	// ErrInvalidSnapshotRequest is returned when a snapshot request fails validation
+	ErrInvalidSnapshotRequest = entity.NewError(entity.ErrValidation, "invalid snapshot request")
+	// ErrSnapshotRequestNotFound is returned when a snapshot request does not exist
+	ErrSnapshotRequestNotFound = entity.NewError(entity.ErrNotFound, "snapshot request not found")
+)
+
+// snapshotLock is the lock held by the replica signalling and tracking snapshot requests
+const snapshotLock = "pipeline-snapshots"
+
+// snapshotColumn matches the column names accepted in snapshot conditions
+var snapshotColumn = regexp.MustCompile(`^[A-Za-z_][A-Za-z0-9_]*$`)
+
+// snapshotOperators maps the operators of snapshot conditions to SQL
+var snapshotOperators = map[string]string{
+	entity.SnapshotOperator.Equal:          "=",
+	entity.SnapshotOperator.NotEqual:       "<>",
+	entity.SnapshotOperator.Less:           "<",
+	entity.SnapshotOperator.LessOrEqual:    "<=",
+	entity.SnapshotOperator.Greater:        ">",
+	entity.SnapshotOperator.GreaterOrEqual: ">=",
+	entity.SnapshotOperator.In:             "IN",
+}
+
+// SnapshotService triggers Debezium incremental snapshots through the signalling table and
+// tracks their progress from the watermark signals Debezium writes back. Requests are signalled
+// one at a time, so every window written after a request's signal belongs to that request.
+type SnapshotService struct {
+	signalRepo        repository.SignalRepository
+	lockRepo          repository.LockRepository
+	publicationTables []string
+	quietPeriod       time.Duration
+	startTimeout      time.Duration
+	signalRetention   time.Duration
+}
+
+// NewSnapshotService creates a new SnapshotService for the tables of the publication
+func NewSnapshotService(signalRepo repository.SignalRepository, lockRepo repository.LockRepository, publicationTables []string,
+	quietPeriod, startTimeout, signalRetention time.Duration) *SnapshotService {
+	return &SnapshotService{
+		signalRepo:        signalRepo,
+		lockRepo:          lockRepo,
+		publicationTables: publicationTables,
+		quietPeriod:       quietPeriod,
+		startTimeout:      startTimeout,
+		signalRetention:   signalRetention,
+	}
+}
+
+// snapshotSignalData is the payload of a Debezium execute-snapshot signal
+type snapshotSignalData struct {
+	DataCollections      []string                  `json:"data-collections"`
+	Type                 string                    `json:"type"`
+	AdditionalConditions []snapshotSignalCondition `json:"additional-conditions,omitempty"`
+}
+
+type snapshotSignalCondition struct {
+	DataCollection string `json:"data-collection"`
+	Filter         string `json:"filter"`
+}
+
+// GetAllSnapshots retrieves all snapshot requests
+func (s *SnapshotService) GetAllSnapshots(ctx context.Context) ([]entity.SnapshotRequest, error) {
+	return s.signalRepo.FindAllSnapshotRequests(ctx)
+}
+
+// GetSnapshotByID retrieves a snapshot request by its ID
+func (s *SnapshotService) GetSnapshotByID(ctx context.Context, id string) (*entity.SnapshotRequest, error) {
+	request, err := s.signalRepo.FindSnapshotRequestByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if request == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSnapshotRequestNotFound, id)
+	}
+	return request, nil
+}
+
+// RequestSnapshot validates and queues a snapshot request. Only tables of the publication can
+// be snapshotted, and conditions are rendered as SQL by the service rather than passed through.
+func (s *SnapshotService) RequestSnapshot(ctx context.Context, request *entity.SnapshotRequest) error {
+	if len(request.DataCollections) == 0 {
+		return entity.NewFieldError(ErrInvalidSnapshotRequest, "dataCollections", "is required")
+	}
+
+	collections := make(map[string]bool, len(request.DataCollections))
+	for i, collection := range request.DataCollections {
+		field := fmt.Sprintf("dataCollections[%d]", i)
+		collection = qualifyDataCollection(collection)
+		if collection == "" {
+			return entity.NewFieldError(ErrInvalidSnapshotRequest, field, "must not be empty")
+		}
+		if !slices.Contains(s.publicationTables, collection) {
+			return entity.NewFieldError(ErrInvalidSnapshotRequest, field, fmt.Sprintf("%q is not a table of the publication", collection))
+		}
+		request.DataCollections[i] = collection
+		collections[collection] = true
+	}
+
+	for i, condition := range request.Conditions {
+		field := fmt.Sprintf("conditions[%d]", i)
+		condition.DataCollection = qualifyDataCollection(condition.DataCollection)
+		if !collections[condition.DataCollection] {
+			return entity.NewFieldError(ErrInvalidSnapshotRequest, field+".dataCollection",
+				fmt.Sprintf("%q does not match a requested data collection", condition.DataCollection))
+		}
+		if !snapshotColumn.MatchString(condition.Column) {
+			return entity.NewFieldError(ErrInvalidSnapshotRequest, field+".column", "must be a column name")
+		}
+		if _, ok := snapshotOperators[condition.Operator]; !ok {
+			return entity.NewFieldError(ErrInvalidSnapshotRequest, field+".operator",
+				fmt.Sprintf("%q is not one of eq, ne, lt, lte, gt, gte, in", condition.Operator))
+		}
+		if condition.Operator == entity.SnapshotOperator.In && len(condition.Values) == 0 {
+			return entity.NewFieldError(ErrInvalidSnapshotRequest, field+".values", "needs at least one value")
+		}
+		if condition.Operator != entity.SnapshotOperator.In && len(condition.Values) != 1 {
+			return entity.NewFieldError(ErrInvalidSnapshotRequest, field+".values", "needs exactly one value")
+		}
+		request.Conditions[i] = condition
+	}
+
+	now := time.Now()
+	request.ID = uuid.NewString()
+	request.Status = entity.SnapshotStatus.Pending
+	request.ChunksCompleted = 0
+	request.SignalledAt = nil
+	request.CreatedAt = now
+	request.UpdatedAt = now
+
+	return s.signalRepo.CreateSnapshotRequest(ctx, request)
+}
+
+// TrackProgress updates the signalled snapshot request from the window signals written after
+// its signal, and signals the oldest queued request once none is in progress. A request
+// completes once no window has been written for the configured quiet period, and times out
+// when no window is written within the start timeout. Only one replica tracks snapshots at a time.
+func (s *SnapshotService) TrackProgress(ctx context.Context) error {
+	release, acquired, err := s.lockRepo.TryLock(ctx, snapshotLock)
+	if err != nil || !acquired {
+		return err
+	}
+	defer release()
+
+	requests, err := s.signalRepo.FindSnapshotRequestsByStatus(ctx, entity.SnapshotStatus.Pending, entity.SnapshotStatus.Running)
+	if err != nil {
+		return err
+	}
+
+	var queued []*entity.SnapshotRequest
+	for i := range requests {
+		request := &requests[i]
+		if request.SignalledAt == nil {
+			queued = append(queued, request)
+			continue
+		}
+		if err := s.trackRequest(ctx, request); err != nil {
+			return err
+		}
+		if !entity.IsFinishedSnapshotStatus(request.Status) {
+			return nil
+		}
+	}
+
+	if len(queued) == 0 {
+		return nil
+	}
+	return s.signal(ctx, queued[0])
+}
+
+// trackRequest updates a signalled request from the windows written after its signal
+func (s *SnapshotService) trackRequest(ctx context.Context, request *entity.SnapshotRequest) error {
+	windows, err := s.signalRepo.FindSignalsAfter(ctx, request.ID,
+		entity.SignalType.SnapshotWindowOpen, entity.SignalType.SnapshotWindowClose)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	if len(windows) == 0 {
+		// A snapshot that never started, such as one Debezium rejected, times out rather than
+		// holding up the queue; it is not reported as completed since nothing was snapshotted
+		if now.Sub(*request.SignalledAt) >= s.startTimeout {
+			log.Printf("Snapshot request %s timed out: no snapshot window was written within %s of its signal", request.ID, s.startTimeout)
+			request.Status = entity.SnapshotStatus.TimedOut
+			request.UpdatedAt = now
+			return s.signalRepo.UpdateSnapshotRequest(ctx, request)
+		}
+		return nil
+	}
+
+	chunks := 0
+	for _, window := range windows {
+		if window.Type == entity.SignalType.SnapshotWindowClose {
+			chunks++
+		}
+	}
+	first, last := windows[0], windows[len(windows)-1]
+
+	request.Status = entity.SnapshotStatus.Running
+	request.ChunksCompleted = chunks
+	if request.StartedAt == nil {
+		request.StartedAt = &first.CreatedAt
+	}
+	request.LastChunkAt = &last.CreatedAt
+	if last.Type == entity.SignalType.SnapshotWindowClose && now.Sub(last.CreatedAt) >= s.quietPeriod {
+		request.Status = entity.SnapshotStatus.Completed
+		request.CompletedAt = &last.CreatedAt
+	}
+	request.UpdatedAt = now
+
+	return s.signalRepo.UpdateSnapshotRequest(ctx, request)
+}
+
+// signal writes the execute-snapshot signal of a queued request, using the request ID as
+// the signal ID
+func (s *SnapshotService) signal(ctx context.Context, request *entity.SnapshotRequest) error {
+	signalData := snapshotSignalData{
+		DataCollections: request.DataCollections,
+		Type:            "incremental",
+	}
+	for _, collection := range request.DataCollections {
+		if filter := snapshotFilter(request.Conditions, collection); filter != "" {
+			signalData.AdditionalConditions = append(signalData.AdditionalConditions, snapshotSignalCondition{
+				DataCollection: collection,
+				Filter:         filter,
+			})
+		}
+	}
+	data, err := json.Marshal(signalData)
+	if err != nil {
+		return err
+	}
+
+	// The signal is written without a creation time so the database sets it, like the windows
+	// Debezium writes; windows are found by comparing them, which clock skew must not upset
+	now := time.Now()
+	request.SignalledAt = &now
+	request.UpdatedAt = now
+	signal := &entity.Signal{
+		ID:   request.ID,
+		Type: entity.SignalType.ExecuteSnapshot,
+		Data: string(data),
+	}
+
+	return s.signalRepo.SignalSnapshotRequest(ctx, request, signal)
+}
+
+// Run tracks snapshot progress and prunes old signals every interval until ctx is cancelled
+func (s *SnapshotService) Run(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			if err := s.TrackProgress(ctx); err != nil {
+				log.Printf("Failed to track snapshot progress: %v", err)
+			}
+			if err := s.signalRepo.DeleteSignalsBefore(ctx, time.Now().Add(-s.signalRetention)); err != nil {
+				log.Printf("Failed to prune Debezium signals: %v", err)
+			}
+		}
+	}
+}
+
+// snapshotFilter renders the conditions on a data collection as one SQL predicate, quoting
+// the column names and values
+func snapshotFilter(conditions []entity.SnapshotCondition, collection string) string {
+	var predicates []string
+	for _, condition := range conditions {
+		if condition.DataCollection != collection {
+			continue
+		}
+		values := make([]string, len(condition.Values))
+		for i, value := range condition.Values {
+			values[i] = "'" + strings.ReplaceAll(value, "'", "''") + "'"
+		}
+		column := `"` + strings.ReplaceAll(condition.Column, `"`, `""`) + `"`
+		operator := snapshotOperators[condition.Operator]
+		if condition.Operator == entity.SnapshotOperator.In {
+			predicates = append(predicates, fmt.Sprintf("%s %s (%s)", column, operator, strings.Join(values, ", ")))
+		} else {
+			predicates = append(predicates, fmt.Sprintf("%s %s %s", column, operator, values[0]))
+		}
+	}
+	return strings.Join(predicates, " AND ")
+}
+
+// qualifyDataCollection prefixes unqualified table names with the public schema
+func qualifyDataCollection(name string) string {
+	name = strings.TrimSpace(name)
+	if name == "" || strings.Contains(name, ".") {
+		return name
+	}
+	return "public." + name
+}
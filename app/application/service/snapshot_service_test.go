@@ -0,0 +1,116 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// memorySignalRepository keeps snapshot requests and the windows written after them in
+// memory. Methods the tests do not use are left to the embedded interface and panic.
+type memorySignalRepository struct {
+	repository.SignalRepository
+	requests []entity.SnapshotRequest
+	// windows holds the window signals written after each request's signal
+	windows   map[string][]entity.Signal
+	signalled []string
+}
+
+func (r *memorySignalRepository) FindSnapshotRequestsByStatus(_ context.Context, statuses ...string) ([]entity.SnapshotRequest, error) {
+	var requests []entity.SnapshotRequest
+	for _, request := range r.requests {
+		for _, status := range statuses {
+			if request.Status == status {
+				requests = append(requests, request)
+			}
+		}
+	}
+	return requests, nil
+}
+
+func (r *memorySignalRepository) UpdateSnapshotRequest(_ context.Context, request *entity.SnapshotRequest) error {
+	for i := range r.requests {
+		if r.requests[i].ID == request.ID {
+			r.requests[i] = *request
+		}
+	}
+	return nil
+}
+
+func (r *memorySignalRepository) SignalSnapshotRequest(ctx context.Context, request *entity.SnapshotRequest, signal *entity.Signal) error {
+	r.signalled = append(r.signalled, signal.ID)
+	return r.UpdateSnapshotRequest(ctx, request)
+}
+
+func (r *memorySignalRepository) FindSignalsAfter(_ context.Context, signalID string, _ ...string) ([]entity.Signal, error) {
+	return r.windows[signalID], nil
+}
+
+// freeLockRepository grants every lock
+type freeLockRepository struct{}
+
+func (freeLockRepository) TryLock(context.Context, string) (func(), bool, error) {
+	return func() {}, true, nil
+}
+
+func TestSnapshotServiceTrackProgress(t *testing.T) {
+	now := time.Now()
+	ago := func(d time.Duration) *time.Time {
+		at := now.Add(-d)
+		return &at
+	}
+	window := func(signalType string, d time.Duration) entity.Signal {
+		return entity.Signal{Type: signalType, CreatedAt: *ago(d)}
+	}
+
+	tests := []struct {
+		name       string
+		signalled  *time.Time
+		windows    []entity.Signal
+		wantStatus string
+		// wantNext reports whether the queued request is signalled
+		wantNext bool
+	}{
+		{name: "waiting to start", signalled: ago(time.Minute), wantStatus: entity.SnapshotStatus.Pending},
+		{name: "never started", signalled: ago(5 * time.Minute), wantStatus: entity.SnapshotStatus.TimedOut, wantNext: true},
+		{name: "running", signalled: ago(10 * time.Minute),
+			windows:    []entity.Signal{window(entity.SignalType.SnapshotWindowOpen, 2*time.Second)},
+			wantStatus: entity.SnapshotStatus.Running},
+		{name: "quiet", signalled: ago(10 * time.Minute),
+			windows: []entity.Signal{
+				window(entity.SignalType.SnapshotWindowOpen, 2*time.Minute),
+				window(entity.SignalType.SnapshotWindowClose, time.Minute),
+			},
+			wantStatus: entity.SnapshotStatus.Completed, wantNext: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			signalRepo := &memorySignalRepository{
+				requests: []entity.SnapshotRequest{
+					{ID: "signalled", Status: entity.SnapshotStatus.Pending, SignalledAt: tt.signalled},
+					{ID: "queued", Status: entity.SnapshotStatus.Pending},
+				},
+				windows: map[string][]entity.Signal{"signalled": tt.windows},
+			}
+			snapshotService := NewSnapshotService(signalRepo, freeLockRepository{}, nil, 30*time.Second, 5*time.Minute, 168*time.Hour)
+
+			if err := snapshotService.TrackProgress(context.Background()); err != nil {
+				t.Fatalf("TrackProgress() error = %v", err)
+			}
+			request := signalRepo.requests[0]
+			if request.Status != tt.wantStatus {
+				t.Errorf("status = %s, want %s", request.Status, tt.wantStatus)
+			}
+			if request.Status == entity.SnapshotStatus.TimedOut && request.CompletedAt != nil {
+				t.Errorf("a snapshot that never started has completedAt %s", request.CompletedAt)
+			}
+			if next := len(signalRepo.signalled) == 1; next != tt.wantNext {
+				t.Errorf("signalled %v, want the queued request signalled: %v", signalRepo.signalled, tt.wantNext)
+			}
+		})
+	}
+}
@@ -0,0 +1,61 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strings"
+)
+
+// tokenHeader is the encoded JOSE header of the tokens signed here, JWTs signed with HMAC SHA-256
+var tokenHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// signToken encodes claims as a JWT signed with secret
+func signToken(secret []byte, claims any) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	signingInput := tokenHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return signingInput + "." + base64.RawURLEncoding.EncodeToString(tokenMAC(secret, signingInput)), nil
+}
+
+// verifyToken checks that token is a JWT signed with secret using HS256 and decodes its
+// claims. Other algorithms, including none, are rejected.
+func verifyToken(secret []byte, token string, claims any) error {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return errors.New("token is not a JWT")
+	}
+
+	var header struct {
+		Algorithm string `json:"alg"`
+	}
+	encodedHeader, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil || json.Unmarshal(encodedHeader, &header) != nil {
+		return errors.New("token header is malformed")
+	}
+	if header.Algorithm != "HS256" {
+		return errors.New("token is not signed with HS256")
+	}
+
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, tokenMAC(secret, parts[0]+"."+parts[1])) {
+		return errors.New("token signature is invalid")
+	}
+
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || json.Unmarshal(payload, claims) != nil {
+		return errors.New("token claims are malformed")
+	}
+	return nil
+}
+
+// tokenMAC returns the HMAC SHA-256 of a token's signing input
+func tokenMAC(secret []byte, signingInput string) []byte {
+	mac := hmac.New(sha256.New, secret)
+	mac.Write([]byte(signingInput))
+	return mac.Sum(nil)
+}
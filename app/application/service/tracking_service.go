@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// trackingClaims are the claims of a tracking token
+type trackingClaims struct {
+	OrderID string `json:"ord"`
+	// Subject and Role are those of the principal the token was issued to
+	Subject string `json:"sub"`
+	Role    string `json:"role"`
+	// Expiry is when the token expires, in seconds since the Unix epoch
+	Expiry int64 `json:"exp"`
+}
+
+// TrackingService issues and checks order tracking tokens. A token grants read access to the
+// changes of one order, for pages such as customer order tracking whose browser holds no other
+// credentials. Tokens are JWTs signed with HS256, so every replica sharing the secret accepts
+// them; they name the order, the principal they were issued to and their expiry.
+type TrackingService struct {
+	orderRepo repository.OrderRepository
+	secret    []byte
+	ttl       time.Duration
+}
+
+// NewTrackingService creates a new TrackingService signing tokens with secret that are valid for ttl
+func NewTrackingService(orderRepo repository.OrderRepository, secret []byte, ttl time.Duration) *TrackingService {
+	return &TrackingService{
+		orderRepo: orderRepo,
+		secret:    secret,
+		ttl:       ttl,
+	}
+}
+
+// IssueToken returns a tracking token of an order for a principal that may access it, and
+// when the token expires
+func (s *TrackingService) IssueToken(ctx context.Context, principal *entity.Principal, orderID string) (string, time.Time, error) {
+	if principal == nil {
+		return "", time.Time{}, fmt.Errorf("%w: tracking tokens are issued to authenticated callers", entity.ErrAuthenticationRequired)
+	}
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if order == nil {
+		return "", time.Time{}, fmt.Errorf("%w: %s", entity.ErrOrderNotFound, orderID)
+	}
+	if !principal.CanAccessOrder(order) {
+		return "", time.Time{}, fmt.Errorf("%w: %s", entity.ErrOrderAccessDenied, orderID)
+	}
+
+	expiresAt := time.Now().Add(s.ttl).Truncate(time.Second)
+	token, err := signToken(s.secret, trackingClaims{
+		OrderID: orderID,
+		Subject: principal.Subject,
+		Role:    principal.Role,
+		Expiry:  expiresAt.Unix(),
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	return token, expiresAt, nil
+}
+
+// Authorize checks that token grants access to an order and returns the order. The token
+// must not have expired, and the principal it was issued to must still be able to access the order.
+func (s *TrackingService) Authorize(ctx context.Context, orderID, token string) (*entity.Order, error) {
+	var claims trackingClaims
+	if err := verifyToken(s.secret, token, &claims); err != nil {
+		return nil, fmt.Errorf("%w for order %s: %v", entity.ErrInvalidTrackingToken, orderID, err)
+	}
+	switch {
+	case claims.OrderID != orderID:
+		return nil, fmt.Errorf("%w for order %s: token is for another order", entity.ErrInvalidTrackingToken, orderID)
+	case claims.Expiry == 0:
+		return nil, fmt.Errorf("%w for order %s: token has no expiry", entity.ErrInvalidTrackingToken, orderID)
+	case !time.Now().Before(time.Unix(claims.Expiry, 0)):
+		return nil, fmt.Errorf("%w for order %s", entity.ErrTrackingTokenExpired, orderID)
+	case claims.Subject == "" || !entity.IsValidPrincipalRole(claims.Role):
+		return nil, fmt.Errorf("%w for order %s: token has no principal", entity.ErrInvalidTrackingToken, orderID)
+	}
+
+	order, err := s.orderRepo.FindByID(ctx, orderID)
+	if err != nil {
+		return nil, err
+	}
+	if order == nil {
+		return nil, fmt.Errorf("%w: %s", entity.ErrOrderNotFound, orderID)
+	}
+	principal := entity.Principal{Subject: claims.Subject, Role: claims.Role}
+	if !principal.CanAccessOrder(order) {
+		return nil, fmt.Errorf("%w for order %s: its principal may no longer access the order", entity.ErrInvalidTrackingToken, orderID)
+	}
+	return order, nil
+}
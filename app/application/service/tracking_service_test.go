@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+func newTrackingService(secret string) (*TrackingService, *versionedOrderRepository) {
+	orderRepo := &versionedOrderRepository{orders: map[string]entity.Order{
+		"order-1": {ID: "order-1", CustomerID: "511", Status: entity.OrderStatus.New},
+		"order-2": {ID: "order-2", CustomerID: "512", Status: entity.OrderStatus.New},
+	}}
+	return NewTrackingService(orderRepo, []byte(secret), time.Hour), orderRepo
+}
+
+func TestTrackingServiceIssueToken(t *testing.T) {
+	customer := &entity.Principal{Subject: "511", Role: entity.PrincipalRole.Customer}
+	tests := []struct {
+		name      string
+		principal *entity.Principal
+		orderID   string
+		// wantErr is the error of a token that is not issued, checked with its kind
+		wantErr, wantKind error
+	}{
+		{name: "owner", principal: customer, orderID: "order-1"},
+		{name: "admin", principal: &entity.Principal{Subject: "ops", Role: entity.PrincipalRole.Admin}, orderID: "order-2"},
+		{name: "anonymous", orderID: "order-1",
+			wantErr: entity.ErrAuthenticationRequired, wantKind: entity.ErrUnauthenticated},
+		{name: "another customer's order", principal: customer, orderID: "order-2",
+			wantErr: entity.ErrOrderAccessDenied, wantKind: entity.ErrForbidden},
+		{name: "unknown role", principal: &entity.Principal{Subject: "511", Role: "root"}, orderID: "order-1",
+			wantErr: entity.ErrOrderAccessDenied, wantKind: entity.ErrForbidden},
+		{name: "missing order", principal: customer, orderID: "order-3",
+			wantErr: entity.ErrOrderNotFound, wantKind: entity.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trackingService, _ := newTrackingService("tracking-secret")
+			token, expiresAt, err := trackingService.IssueToken(context.Background(), tt.principal, tt.orderID)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, tt.wantKind) || token != "" {
+					t.Fatalf("IssueToken() = %q, %v, want %v", token, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("IssueToken() error = %v", err)
+			}
+			if until := time.Until(expiresAt); until <= 59*time.Minute || until > time.Hour {
+				t.Errorf("IssueToken() expires in %s, want the TTL", until)
+			}
+			if _, err := trackingService.Authorize(context.Background(), tt.orderID, token); err != nil {
+				t.Errorf("Authorize() of the issued token error = %v", err)
+			}
+		})
+	}
+}
+
+func TestTrackingServiceAuthorize(t *testing.T) {
+	secret := []byte("tracking-secret")
+	expiry := time.Now().Add(time.Hour).Unix()
+	sign := func(secret []byte, claims trackingClaims) string {
+		token, err := signToken(secret, claims)
+		if err != nil {
+			t.Fatalf("signToken() error = %v", err)
+		}
+		return token
+	}
+	customer := func(orderID, subject string, expiry int64) trackingClaims {
+		return trackingClaims{OrderID: orderID, Subject: subject, Role: entity.PrincipalRole.Customer, Expiry: expiry}
+	}
+
+	tests := []struct {
+		name    string
+		token   string
+		orderID string
+		// reassign moves order-1 to another customer after the token was issued
+		reassign bool
+		wantErr  error
+		wantKind error
+	}{
+		{name: "valid", token: sign(secret, customer("order-1", "511", expiry)), orderID: "order-1"},
+		{name: "admin", token: sign(secret, trackingClaims{OrderID: "order-1", Subject: "ops", Role: entity.PrincipalRole.Admin, Expiry: expiry}),
+			orderID: "order-1"},
+		{name: "admin after reassignment", token: sign(secret, trackingClaims{OrderID: "order-1", Subject: "ops", Role: entity.PrincipalRole.Admin, Expiry: expiry}),
+			orderID: "order-1", reassign: true},
+		{name: "another order", token: sign(secret, customer("order-2", "512", expiry)), orderID: "order-1",
+			wantErr: entity.ErrInvalidTrackingToken, wantKind: entity.ErrForbidden},
+		{name: "expired", token: sign(secret, customer("order-1", "511", time.Now().Add(-time.Second).Unix())), orderID: "order-1",
+			wantErr: entity.ErrTrackingTokenExpired, wantKind: entity.ErrUnauthenticated},
+		{name: "no expiry", token: sign(secret, customer("order-1", "511", 0)), orderID: "order-1",
+			wantErr: entity.ErrInvalidTrackingToken, wantKind: entity.ErrForbidden},
+		{name: "no subject", token: sign(secret, customer("order-1", "", expiry)), orderID: "order-1",
+			wantErr: entity.ErrInvalidTrackingToken, wantKind: entity.ErrForbidden},
+		{name: "unknown role", token: sign(secret, trackingClaims{OrderID: "order-1", Subject: "511", Role: "root", Expiry: expiry}), orderID: "order-1",
+			wantErr: entity.ErrInvalidTrackingToken, wantKind: entity.ErrForbidden},
+		{name: "subject no longer the customer", token: sign(secret, customer("order-1", "511", expiry)), orderID: "order-1", reassign: true,
+			wantErr: entity.ErrInvalidTrackingToken, wantKind: entity.ErrForbidden},
+		{name: "other secret", token: sign([]byte("other-secret"), customer("order-1", "511", expiry)), orderID: "order-1",
+			wantErr: entity.ErrInvalidTrackingToken, wantKind: entity.ErrForbidden},
+		{name: "not a JWT", token: "q9V2", orderID: "order-1",
+			wantErr: entity.ErrInvalidTrackingToken, wantKind: entity.ErrForbidden},
+		{name: "missing order", token: sign(secret, customer("order-3", "511", expiry)), orderID: "order-3",
+			wantErr: entity.ErrOrderNotFound, wantKind: entity.ErrNotFound},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			trackingService, orderRepo := newTrackingService(string(secret))
+			if tt.reassign {
+				order := orderRepo.orders["order-1"]
+				order.CustomerID = "512"
+				orderRepo.orders["order-1"] = order
+			}
+
+			order, err := trackingService.Authorize(context.Background(), tt.orderID, tt.token)
+			if tt.wantErr != nil {
+				if !errors.Is(err, tt.wantErr) || !errors.Is(err, tt.wantKind) {
+					t.Fatalf("Authorize() = %+v, %v, want %v", order, err, tt.wantErr)
+				}
+				return
+			}
+			if err != nil || order.ID != tt.orderID {
+				t.Fatalf("Authorize() = %+v, %v, want order %s", order, err, tt.orderID)
+			}
+		})
+	}
+}
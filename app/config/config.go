@@ -4,7 +4,9 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
+	"github.com/elastic/go-elasticsearch/v8"
 	"github.com/spf13/viper"
 	"gorm.io/driver/postgres"
 	"gorm.io/gorm"
@@ -13,11 +15,26 @@ import (
 
 var DB *gorm.DB
 
+var ES *elasticsearch.Client
+
 // Config holds all configuration for the application
 type Config struct {
 	PostgreSQL    PostgreSQLConfig    `mapstructure:"postgres"`
 	Elasticsearch ElasticsearchConfig `mapstructure:"elasticsearch"`
 	Server        ServerConfig        `mapstructure:"server"`
+	Pipeline      PipelineConfig      `mapstructure:"pipeline"`
+	Orders        OrdersConfig        `mapstructure:"orders"`
+	Heartbeat     HeartbeatConfig     `mapstructure:"heartbeat"`
+	Reconcile     ReconcileConfig     `mapstructure:"reconcile"`
+	Schema        SchemaConfig        `mapstructure:"schema"`
+	Automation    AutomationConfig    `mapstructure:"automation"`
+	Idempotency   IdempotencyConfig   `mapstructure:"idempotency"`
+	OpenAPI       OpenAPIConfig       `mapstructure:"openapi"`
+	GRPC          GRPCConfig          `mapstructure:"grpc"`
+	GraphQL       GraphQLConfig       `mapstructure:"graphql"`
+	Stream        StreamConfig        `mapstructure:"stream"`
+	Tracking      TrackingConfig      `mapstructure:"tracking"`
+	Auth          AuthConfig          `mapstructure:"auth"`
 }
 
 // PostgreSQLConfig holds PostgreSQL connection configuration
@@ -31,9 +48,12 @@ type PostgreSQLConfig struct {
 
 // ElasticsearchConfig holds Elasticsearch connection configuration
 type ElasticsearchConfig struct {
-	URL      string `mapstructure:"url"`
-	Username string `mapstructure:"username"`
-	Password string `mapstructure:"password"`
+	URL        string `mapstructure:"url"`
+	Username   string `mapstructure:"username"`
+	Password   string `mapstructure:"password"`
+	OrderIndex string `mapstructure:"order_index"`
+	// OrderHistoryIndex receives the order_status_history table
+	OrderHistoryIndex string `mapstructure:"order_history_index"`
 }
 
 // ServerConfig holds server configuration
@@ -41,6 +61,139 @@ type ServerConfig struct {
 	Port string `mapstructure:"port"`
 }
 
+// GRPCConfig holds gRPC server configuration
+type GRPCConfig struct {
+	// Port is the port of the gRPC server; empty disables it
+	Port string `mapstructure:"port"`
+	// WatchBuffer is how many events a WatchOrders stream may fall behind before it is ended
+	WatchBuffer int `mapstructure:"watch_buffer"`
+}
+
+// GraphQLConfig holds GraphQL endpoint configuration
+type GraphQLConfig struct {
+	// MaxDepth is the deepest selection nesting a query may use
+	MaxDepth int `mapstructure:"max_depth"`
+}
+
+// StreamConfig holds order change stream configuration
+type StreamConfig struct {
+	// History is how many missed order events a client resuming a stream may be sent
+	History int `mapstructure:"history"`
+	// Buffer is how many events a stream client may fall behind before its stream is ended
+	Buffer int `mapstructure:"buffer"`
+	// Heartbeat is how often an idle stream sends a keep-alive
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+	// PollInterval is how often the outbox is read for the order events of other replicas
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+}
+
+// AuthConfig holds API authentication configuration
+type AuthConfig struct {
+	// Secret verifies the HS256 bearer tokens of API callers; empty accepts no tokens
+	Secret string `mapstructure:"secret"`
+}
+
+// TrackingConfig holds order tracking WebSocket configuration
+type TrackingConfig struct {
+	// Secret signs the tracking tokens; empty uses a random secret valid until restart
+	Secret string `mapstructure:"secret"`
+	// Buffer is how many events a tracking connection may fall behind before it is closed
+	Buffer int `mapstructure:"buffer"`
+	// TokenTTL is how long a tracking token may be used to subscribe to its order
+	TokenTTL time.Duration `mapstructure:"token_ttl"`
+	// Heartbeat is how often tracking connections are pinged
+	Heartbeat time.Duration `mapstructure:"heartbeat"`
+	// MaxSubscriptions is how many orders one tracking connection may track
+	MaxSubscriptions int `mapstructure:"max_subscriptions"`
+}
+
+// PipelineConfig holds CDC pipeline configuration
+type PipelineConfig struct {
+	SnapshotPollInterval time.Duration `mapstructure:"snapshot_poll_interval"`
+	SnapshotQuietPeriod  time.Duration `mapstructure:"snapshot_quiet_period"`
+	SnapshotStartTimeout time.Duration `mapstructure:"snapshot_start_timeout"`
+	SignalRetention      time.Duration `mapstructure:"signal_retention"`
+	Publication          string        `mapstructure:"publication"`
+	PublicationTables    []string      `mapstructure:"publication_tables"`
+	ReplicaIdentity      string        `mapstructure:"replica_identity"`
+	SlotCheckInterval    time.Duration `mapstructure:"slot_check_interval"`
+	SlotMaxRetainedWALMB int64         `mapstructure:"slot_max_retained_wal_mb"`
+	AllowSlotDrop        bool          `mapstructure:"allow_slot_drop"`
+	OutboxPublication    string        `mapstructure:"outbox_publication"`
+	OutboxRetention      time.Duration `mapstructure:"outbox_retention"`
+	OutboxPruneInterval  time.Duration `mapstructure:"outbox_prune_interval"`
+}
+
+// OrdersConfig holds order lifecycle configuration
+type OrdersConfig struct {
+	// PurgeAfter is how long deleted orders are kept before being purged; 0 keeps them
+	PurgeAfter    time.Duration `mapstructure:"purge_after"`
+	PurgeInterval time.Duration `mapstructure:"purge_interval"`
+	// AllowClientIDs lets clients supply id and orderId instead of having them generated
+	AllowClientIDs bool `mapstructure:"allow_client_ids"`
+	// BulkAtomic runs bulk requests in one transaction; otherwise each operation stands alone
+	BulkAtomic        bool `mapstructure:"bulk_atomic"`
+	BulkMaxOperations int  `mapstructure:"bulk_max_operations"`
+}
+
+// HeartbeatConfig holds replication heartbeat prober configuration
+type HeartbeatConfig struct {
+	ID           string        `mapstructure:"id"`
+	Index        string        `mapstructure:"index"`
+	Interval     time.Duration `mapstructure:"interval"`
+	Timeout      time.Duration `mapstructure:"timeout"`
+	PollInterval time.Duration `mapstructure:"poll_interval"`
+	Samples      int           `mapstructure:"samples"`
+	SLO          time.Duration `mapstructure:"slo"`
+}
+
+// ReconcileConfig holds PostgreSQL to Elasticsearch reconciliation configuration
+type ReconcileConfig struct {
+	Interval  time.Duration `mapstructure:"interval"`
+	Repair    bool          `mapstructure:"repair"`
+	ChunkSize int           `mapstructure:"chunk_size"`
+	MaxIDs    int           `mapstructure:"max_ids"`
+}
+
+// AutomationConfig holds scheduled order status automation configuration
+type AutomationConfig struct {
+	Interval  time.Duration          `mapstructure:"interval"`
+	BatchSize int                    `mapstructure:"batch_size"`
+	Rules     []AutomationRuleConfig `mapstructure:"rules"`
+}
+
+// AutomationRuleConfig holds one automation rule; action is transition or flag
+type AutomationRuleConfig struct {
+	Name         string        `mapstructure:"name"`
+	Status       string        `mapstructure:"status"`
+	After        time.Duration `mapstructure:"after"`
+	Action       string        `mapstructure:"action"`
+	TargetStatus string        `mapstructure:"target_status"`
+	Reason       string        `mapstructure:"reason"`
+}
+
+// IdempotencyConfig holds Idempotency-Key configuration
+type IdempotencyConfig struct {
+	// TTL is how long a key and its response are kept for replays
+	TTL time.Duration `mapstructure:"ttl"`
+	// Lease is how long a request may hold its key before a retry may take it over
+	Lease         time.Duration `mapstructure:"lease"`
+	PruneInterval time.Duration `mapstructure:"prune_interval"`
+}
+
+// OpenAPIConfig holds API specification configuration
+type OpenAPIConfig struct {
+	// Validate checks requests against the specification before they reach the handlers
+	Validate bool `mapstructure:"validate"`
+	// FailOnDrift refuses to start when the routes and the specification disagree
+	FailOnDrift bool `mapstructure:"fail_on_drift"`
+}
+
+// SchemaConfig holds schema change detection configuration
+type SchemaConfig struct {
+	FailOnIncompatible bool `mapstructure:"fail_on_incompatible"`
+}
+
 // LoadConfig loads configuration from environment variables and config files
 func LoadConfig() (*Config, error) {
 	v := viper.New()
@@ -54,7 +207,59 @@ func LoadConfig() (*Config, error) {
 	v.SetDefault("elasticsearch.url", "http://localhost:9200")
 	v.SetDefault("elasticsearch.username", "")
 	v.SetDefault("elasticsearch.password", "")
+	v.SetDefault("elasticsearch.order_index", "dbserver1.public.orders")
+	v.SetDefault("elasticsearch.order_history_index", "dbserver1.public.order_status_history")
 	v.SetDefault("server.port", "8080")
+	v.SetDefault("grpc.port", "9090")
+	v.SetDefault("grpc.watch_buffer", 256)
+	v.SetDefault("graphql.max_depth", 10)
+	v.SetDefault("stream.history", 1000)
+	v.SetDefault("stream.buffer", 256)
+	v.SetDefault("stream.heartbeat", "15s")
+	v.SetDefault("stream.poll_interval", "1s")
+	v.SetDefault("auth.secret", "")
+	v.SetDefault("tracking.secret", "")
+	v.SetDefault("tracking.token_ttl", "1h")
+	v.SetDefault("tracking.buffer", 64)
+	v.SetDefault("tracking.heartbeat", "30s")
+	v.SetDefault("tracking.max_subscriptions", 20)
+	v.SetDefault("pipeline.snapshot_poll_interval", "5s")
+	v.SetDefault("pipeline.snapshot_quiet_period", "30s")
+	v.SetDefault("pipeline.snapshot_start_timeout", "5m")
+	v.SetDefault("pipeline.signal_retention", "168h")
+	v.SetDefault("pipeline.publication", "dbz_publication")
+	v.SetDefault("pipeline.publication_tables", []string{"public.orders", "public.order_status_history", "public.debezium_signal", "public.heartbeats"})
+	v.SetDefault("pipeline.replica_identity", "DEFAULT")
+	v.SetDefault("pipeline.slot_check_interval", "1m")
+	v.SetDefault("pipeline.slot_max_retained_wal_mb", 1024)
+	v.SetDefault("pipeline.allow_slot_drop", false)
+	v.SetDefault("pipeline.outbox_publication", "dbz_outbox_publication")
+	v.SetDefault("pipeline.outbox_retention", "168h")
+	v.SetDefault("pipeline.outbox_prune_interval", "1h")
+	v.SetDefault("orders.purge_after", "0s")
+	v.SetDefault("orders.purge_interval", "1h")
+	v.SetDefault("orders.allow_client_ids", false)
+	v.SetDefault("orders.bulk_atomic", true)
+	v.SetDefault("orders.bulk_max_operations", 1000)
+	v.SetDefault("heartbeat.id", "")
+	v.SetDefault("heartbeat.index", "dbserver1.public.heartbeats")
+	v.SetDefault("heartbeat.interval", "10s")
+	v.SetDefault("heartbeat.timeout", "60s")
+	v.SetDefault("heartbeat.poll_interval", "100ms")
+	v.SetDefault("heartbeat.samples", 360)
+	v.SetDefault("heartbeat.slo", "5s")
+	v.SetDefault("reconcile.interval", "0s")
+	v.SetDefault("reconcile.repair", false)
+	v.SetDefault("reconcile.chunk_size", 500)
+	v.SetDefault("reconcile.max_ids", 1000)
+	v.SetDefault("schema.fail_on_incompatible", true)
+	v.SetDefault("automation.interval", "5m")
+	v.SetDefault("automation.batch_size", 100)
+	v.SetDefault("idempotency.ttl", "24h")
+	v.SetDefault("idempotency.lease", "1m")
+	v.SetDefault("idempotency.prune_interval", "1h")
+	v.SetDefault("openapi.validate", true)
+	v.SetDefault("openapi.fail_on_drift", true)
 
 	// Read from environment variables
 	v.AutomaticEnv()
@@ -77,10 +282,70 @@ func LoadConfig() (*Config, error) {
 	if err := v.Unmarshal(&config); err != nil {
 		return nil, fmt.Errorf("unable to decode config into struct: %w", err)
 	}
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
 
 	return &config, nil
 }
 
+// intervalSetting is a configured interval and its key, for error messages
+type intervalSetting struct {
+	key   string
+	value time.Duration
+}
+
+// sizeSetting is a configured size and its key, for error messages
+type sizeSetting struct {
+	key   string
+	value int
+}
+
+// validate checks the intervals of the background loops and the configured sizes, which
+// must be positive
+func (c *Config) validate() error {
+	intervals := []intervalSetting{
+		{"pipeline.snapshot_poll_interval", c.Pipeline.SnapshotPollInterval},
+		{"pipeline.snapshot_start_timeout", c.Pipeline.SnapshotStartTimeout},
+		{"heartbeat.interval", c.Heartbeat.Interval},
+		{"heartbeat.poll_interval", c.Heartbeat.PollInterval},
+		{"heartbeat.timeout", c.Heartbeat.Timeout},
+		{"pipeline.slot_check_interval", c.Pipeline.SlotCheckInterval},
+		{"pipeline.outbox_prune_interval", c.Pipeline.OutboxPruneInterval},
+		{"idempotency.lease", c.Idempotency.Lease},
+		{"idempotency.prune_interval", c.Idempotency.PruneInterval},
+		{"stream.heartbeat", c.Stream.Heartbeat},
+		{"stream.poll_interval", c.Stream.PollInterval},
+		{"tracking.token_ttl", c.Tracking.TokenTTL},
+		{"tracking.heartbeat", c.Tracking.Heartbeat},
+	}
+	if c.Orders.PurgeAfter > 0 {
+		// The purge job only runs when deleted orders are purged
+		intervals = append(intervals, intervalSetting{"orders.purge_interval", c.Orders.PurgeInterval})
+	}
+	for _, interval := range intervals {
+		if interval.value <= 0 {
+			return fmt.Errorf("invalid configuration: %s must be a positive duration, got %s", interval.key, interval.value)
+		}
+	}
+
+	sizes := []sizeSetting{
+		{"reconcile.chunk_size", c.Reconcile.ChunkSize},
+		{"reconcile.max_ids", c.Reconcile.MaxIDs},
+		// A stream's buffer must hold at least one event, or every stream is dropped
+		{"stream.buffer", c.Stream.Buffer},
+		{"grpc.watch_buffer", c.GRPC.WatchBuffer},
+		{"tracking.buffer", c.Tracking.Buffer},
+		{"tracking.max_subscriptions", c.Tracking.MaxSubscriptions},
+	}
+	for _, size := range sizes {
+		if size.value <= 0 {
+			return fmt.Errorf("invalid configuration: %s must be a positive number, got %d", size.key, size.value)
+		}
+	}
+	return nil
+}
+
 // ConnectDB connects to the database and initializes the global DB variable
 func ConnectDB(cfg *PostgreSQLConfig) error {
 	// Create connection string
@@ -90,7 +355,8 @@ func ConnectDB(cfg *PostgreSQLConfig) error {
 	// Connect to the database
 	var err error
 	DB, err = gorm.Open(postgres.Open(dsn), &gorm.Config{
-		Logger: logger.Default.LogMode(logger.Info),
+		Logger:         logger.Default.LogMode(logger.Info),
+		TranslateError: true,
 	})
 
 	if err != nil {
@@ -100,3 +366,19 @@ func ConnectDB(cfg *PostgreSQLConfig) error {
 	fmt.Println("Database connection successfully established")
 	return nil
 }
+
+// ConnectES creates the Elasticsearch client and initializes the global ES variable
+func ConnectES(cfg *ElasticsearchConfig) error {
+	var err error
+	ES, err = elasticsearch.NewClient(elasticsearch.Config{
+		Addresses: []string{cfg.URL},
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create elasticsearch client: %w", err)
+	}
+
+	fmt.Println("Elasticsearch client successfully created")
+	return nil
+}
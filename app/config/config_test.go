@@ -0,0 +1,48 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadConfigValidation(t *testing.T) {
+	tests := []struct {
+		// env overrides one setting, as KEY=value
+		env string
+		// wantErr is the setting named by the error, empty when the configuration is valid
+		wantErr string
+	}{
+		{env: "", wantErr: ""},
+		{env: "HEARTBEAT_TIMEOUT=0s", wantErr: "heartbeat.timeout"},
+		{env: "HEARTBEAT_TIMEOUT=-1m", wantErr: "heartbeat.timeout"},
+		{env: "HEARTBEAT_INTERVAL=0s", wantErr: "heartbeat.interval"},
+		{env: "STREAM_POLL_INTERVAL=0s", wantErr: "stream.poll_interval"},
+		{env: "TRACKING_HEARTBEAT=-1s", wantErr: "tracking.heartbeat"},
+		{env: "ORDERS_PURGE_INTERVAL=0s", wantErr: ""},
+		{env: "RECONCILE_CHUNK_SIZE=0", wantErr: "reconcile.chunk_size"},
+		{env: "RECONCILE_MAX_IDS=-5", wantErr: "reconcile.max_ids"},
+		{env: "STREAM_BUFFER=0", wantErr: "stream.buffer"},
+		{env: "STREAM_BUFFER=-1", wantErr: "stream.buffer"},
+		{env: "GRPC_WATCH_BUFFER=0", wantErr: "grpc.watch_buffer"},
+		{env: "TRACKING_BUFFER=0", wantErr: "tracking.buffer"},
+		{env: "TRACKING_MAX_SUBSCRIPTIONS=-1", wantErr: "tracking.max_subscriptions"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.env, func(t *testing.T) {
+			if key, value, ok := strings.Cut(tt.env, "="); ok {
+				t.Setenv(key, value)
+			}
+			_, err := LoadConfig()
+			if tt.wantErr == "" {
+				if err != nil {
+					t.Fatalf("LoadConfig() error = %v", err)
+				}
+				return
+			}
+			if err == nil || !strings.Contains(err.Error(), tt.wantErr+" must be") {
+				t.Fatalf("LoadConfig() error = %v, want one about %s", err, tt.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,53 @@
+package entity
+
+import (
+	"time"
+)
+
+// ErrInvalidAutomationRule is returned when an automation rule is misconfigured
+var ErrInvalidAutomationRule = NewError(ErrValidation, "invalid automation rule")
+
+// AutomationAction represents what an automation rule does with a stale order
+var AutomationAction = struct {
+	Transition string
+	Flag       string
+}{
+	Transition: "transition",
+	Flag:       "flag",
+}
+
+// AutomationRule applies an action to orders that have been in a status for longer than After.
+// Transition rules move the order to TargetStatus; flag rules flag it once for follow-up.
+type AutomationRule struct {
+	Name         string        `json:"name"`
+	Status       string        `json:"status"`
+	After        time.Duration `json:"after"`
+	Action       string        `json:"action"`
+	TargetStatus string        `json:"targetStatus,omitempty"`
+	Reason       string        `json:"reason"`
+}
+
+// OrderFlag marks an order as needing attention according to an automation rule
+type OrderFlag struct {
+	ID        string    `json:"id"`
+	OrderID   string    `json:"orderId"`
+	Rule      string    `json:"rule"`
+	Reason    string    `json:"reason"`
+	FlaggedAt time.Time `json:"flaggedAt"`
+}
+
+// AutomationRuleResult counts what a rule did during one automation run
+type AutomationRuleResult struct {
+	Rule    string `json:"rule"`
+	Matched int    `json:"matched"`
+	Applied int    `json:"applied"`
+	Failed  int    `json:"failed"`
+}
+
+// AutomationRun summarizes one automation run; Skipped is set when another replica held the lock
+type AutomationRun struct {
+	StartedAt  time.Time              `json:"startedAt"`
+	FinishedAt time.Time              `json:"finishedAt"`
+	Skipped    bool                   `json:"skipped"`
+	Results    []AutomationRuleResult `json:"results"`
+}
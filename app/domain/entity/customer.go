@@ -0,0 +1,28 @@
+package entity
+
+import (
+	"time"
+)
+
+var (
+	// ErrInvalidCustomer is returned when a customer fails validation
+	ErrInvalidCustomer = NewError(ErrValidation, "invalid customer")
+
+	// ErrCustomerNotFound is returned when a referenced customer does not exist
+	ErrCustomerNotFound = NewError(ErrNotFound, "customer not found")
+
+	// ErrCustomerHasOrders is returned when deleting a customer that still has orders
+	ErrCustomerHasOrders = NewError(ErrConflict, "customer has orders")
+	// ErrDuplicateCustomer is returned when a customer with the same id already exists
+	ErrDuplicateCustomer = NewError(ErrConflict, "duplicate customer")
+)
+
+// Customer represents a customer placing orders
+type Customer struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	Email     string    `json:"email"`
+	Phone     string    `json:"phone"`
+	CreatedAt time.Time `json:"createdAt"`
+	UpdatedAt time.Time `json:"updatedAt"`
+}
@@ -0,0 +1,76 @@
+package entity
+
+import (
+	"errors"
+	"strings"
+)
+
+// Error kinds. Every domain error is of one kind, which callers can test with errors.Is to
+// decide how to answer it without knowing the specific error.
+var (
+	// ErrNotFound is the kind of errors for resources that do not exist
+	ErrNotFound = errors.New("not found")
+	// ErrValidation is the kind of errors for invalid input
+	ErrValidation = errors.New("validation failed")
+	// ErrUnauthenticated is the kind of errors for callers whose credentials are missing or invalid
+	ErrUnauthenticated = errors.New("unauthenticated")
+	// ErrForbidden is the kind of errors for callers not allowed to access a resource
+	ErrForbidden = errors.New("forbidden")
+	// ErrConflict is the kind of errors for requests that conflict with the current state
+	ErrConflict = errors.New("conflict")
+	// ErrUnavailable is the kind of errors for dependencies that cannot be reached right now
+	ErrUnavailable = errors.New("service unavailable")
+)
+
+// kindError is a sentinel error of one kind
+type kindError struct {
+	kind    error
+	message string
+}
+
+// NewError creates a sentinel error of the given kind with message as its text
+func NewError(kind error, message string) error {
+	return &kindError{kind: kind, message: message}
+}
+
+// Error returns the message of the error
+func (e *kindError) Error() string {
+	return e.message
+}
+
+// Unwrap returns the kind of the error
+func (e *kindError) Unwrap() error {
+	return e.kind
+}
+
+// FieldError describes why one input field is invalid
+type FieldError struct {
+	Field   string `json:"field"`
+	Message string `json:"message"`
+}
+
+// ValidationError is invalid input with details about the offending fields. It is always of
+// kind ErrValidation and also matches the error it wraps, such as ErrInvalidOrder.
+type ValidationError struct {
+	Err    error
+	Fields []FieldError
+}
+
+// NewFieldError creates a validation error for one field, wrapping err
+func NewFieldError(err error, field, message string) *ValidationError {
+	return &ValidationError{Err: err, Fields: []FieldError{{Field: field, Message: message}}}
+}
+
+// Error returns the wrapped error followed by the field details
+func (e *ValidationError) Error() string {
+	details := make([]string, len(e.Fields))
+	for i, field := range e.Fields {
+		details[i] = field.Field + " " + field.Message
+	}
+	return e.Err.Error() + ": " + strings.Join(details, "; ")
+}
+
+// Unwrap returns ErrValidation and the wrapped error
+func (e *ValidationError) Unwrap() []error {
+	return []error{ErrValidation, e.Err}
+}
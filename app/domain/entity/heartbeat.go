@@ -0,0 +1,12 @@
+package entity
+
+import (
+	"time"
+)
+
+// Heartbeat represents a row written periodically to measure replication latency
+type Heartbeat struct {
+	ID     string    `json:"id"`
+	Seq    int64     `json:"seq"`
+	BeatAt time.Time `json:"beatAt"`
+}
@@ -0,0 +1,30 @@
+package entity
+
+import (
+	"time"
+)
+
+var (
+	// ErrIdempotencyKeyReused is returned when an idempotency key is sent again with another request
+	ErrIdempotencyKeyReused = NewError(ErrConflict, "idempotency key was used for a different request")
+	// ErrIdempotencyKeyInProgress is returned when the request first sent with a key has not finished yet
+	ErrIdempotencyKeyInProgress = NewError(ErrConflict, "a request with this idempotency key is in progress")
+)
+
+// IdempotencyRecord is a request made with an idempotency key and, once it completed, its
+// response. Keys are scoped to the principal that sent them, as named by Scope.
+type IdempotencyRecord struct {
+	Scope       string
+	Key         string
+	RequestHash string
+	Completed   bool
+	StatusCode  int
+	ContentType string
+	ETag        string
+	Body        []byte
+	CreatedAt   time.Time
+	// LeaseExpiresAt is when a request that has not completed is presumed lost, so that a
+	// retry may take its key over
+	LeaseExpiresAt time.Time
+	ExpiresAt      time.Time
+}
@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"fmt"
+	"math"
+)
+
+// ErrInvalidMoney is returned for malformed amounts, mismatched currencies or overflowing arithmetic
+var ErrInvalidMoney = NewError(ErrValidation, "invalid money")
+
+// Money is an amount in the minor unit of its currency, e.g. cents for USD
+type Money struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// NewMoney creates Money after validating the ISO 4217 currency code
+func NewMoney(amount int64, currency string) (Money, error) {
+	if !isCurrencyCode(currency) {
+		return Money{}, fmt.Errorf("%w: currency %q is not a three-letter ISO 4217 code", ErrInvalidMoney, currency)
+	}
+	return Money{Amount: amount, Currency: currency}, nil
+}
+
+// Add returns the sum of two amounts in the same currency
+func (m Money) Add(other Money) (Money, error) {
+	if m.Currency != other.Currency {
+		return Money{}, fmt.Errorf("%w: cannot add %s to %s", ErrInvalidMoney, other.Currency, m.Currency)
+	}
+	sum := m.Amount + other.Amount
+	if (other.Amount > 0 && sum < m.Amount) || (other.Amount < 0 && sum > m.Amount) {
+		return Money{}, fmt.Errorf("%w: amount overflow", ErrInvalidMoney)
+	}
+	return Money{Amount: sum, Currency: m.Currency}, nil
+}
+
+// Multiply returns the amount multiplied by quantity
+func (m Money) Multiply(quantity int64) (Money, error) {
+	product := m.Amount * quantity
+	// The division check misses math.MinInt64 * -1, which wraps to itself
+	if (quantity != 0 && product/quantity != m.Amount) || (quantity == -1 && m.Amount == math.MinInt64) {
+		return Money{}, fmt.Errorf("%w: amount overflow", ErrInvalidMoney)
+	}
+	return Money{Amount: product, Currency: m.Currency}, nil
+}
+
+// isCurrencyCode reports whether code looks like an ISO 4217 currency code
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, c := range code {
+		if c < 'A' || c > 'Z' {
+			return false
+		}
+	}
+	return true
+}
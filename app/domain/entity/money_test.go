@@ -0,0 +1,102 @@
+package entity
+
+import (
+	"errors"
+	"math"
+	"testing"
+)
+
+func TestNewMoney(t *testing.T) {
+	tests := []struct {
+		currency string
+		valid    bool
+	}{
+		{"USD", true},
+		{"EUR", true},
+		{"usd", false},
+		{"US", false},
+		{"USDT", false},
+		{"", false},
+		{"U$D", false},
+	}
+
+	for _, tt := range tests {
+		money, err := NewMoney(100, tt.currency)
+		if tt.valid {
+			if err != nil || money != (Money{Amount: 100, Currency: tt.currency}) {
+				t.Errorf("NewMoney(100, %q) = %v, %v, want a valid amount", tt.currency, money, err)
+			}
+		} else if !errors.Is(err, ErrInvalidMoney) || !errors.Is(err, ErrValidation) {
+			t.Errorf("NewMoney(100, %q) error = %v, want ErrInvalidMoney", tt.currency, err)
+		}
+	}
+}
+
+func TestMoneyAdd(t *testing.T) {
+	tests := []struct {
+		name    string
+		a, b    Money
+		want    Money
+		wantErr bool
+	}{
+		{"positive", Money{150, "USD"}, Money{250, "USD"}, Money{400, "USD"}, false},
+		{"negative", Money{150, "USD"}, Money{-200, "USD"}, Money{-50, "USD"}, false},
+		{"zero", Money{0, "EUR"}, Money{0, "EUR"}, Money{0, "EUR"}, false},
+		{"up to the maximum", Money{math.MaxInt64 - 1, "USD"}, Money{1, "USD"}, Money{math.MaxInt64, "USD"}, false},
+		{"down to the minimum", Money{math.MinInt64 + 1, "USD"}, Money{-1, "USD"}, Money{math.MinInt64, "USD"}, false},
+		{"overflow", Money{math.MaxInt64, "USD"}, Money{1, "USD"}, Money{}, true},
+		{"underflow", Money{math.MinInt64, "USD"}, Money{-1, "USD"}, Money{}, true},
+		{"currency mismatch", Money{100, "USD"}, Money{100, "EUR"}, Money{}, true},
+		{"currency mismatch with zero", Money{0, "USD"}, Money{0, "EUR"}, Money{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.a.Add(tt.b)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Fatalf("Add() error = %v, want ErrInvalidMoney", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("Add() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
+
+func TestMoneyMultiply(t *testing.T) {
+	tests := []struct {
+		name     string
+		money    Money
+		quantity int64
+		want     Money
+		wantErr  bool
+	}{
+		{"by a quantity", Money{1999, "USD"}, 3, Money{5997, "USD"}, false},
+		{"by zero", Money{1999, "USD"}, 0, Money{0, "USD"}, false},
+		{"by one", Money{math.MaxInt64, "USD"}, 1, Money{math.MaxInt64, "USD"}, false},
+		{"by minus one", Money{math.MaxInt64, "USD"}, -1, Money{-math.MaxInt64, "USD"}, false},
+		{"overflow", Money{math.MaxInt64/2 + 1, "USD"}, 2, Money{}, true},
+		{"large quantity overflow", Money{3, "USD"}, math.MaxInt64, Money{}, true},
+		{"negative overflow", Money{math.MinInt64, "USD"}, 2, Money{}, true},
+		{"minimum by minus one", Money{math.MinInt64, "USD"}, -1, Money{}, true},
+		{"minus one by minimum", Money{-1, "USD"}, math.MinInt64, Money{}, true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.money.Multiply(tt.quantity)
+			if tt.wantErr {
+				if !errors.Is(err, ErrInvalidMoney) {
+					t.Fatalf("Multiply() error = %v, want ErrInvalidMoney", err)
+				}
+				return
+			}
+			if err != nil || got != tt.want {
+				t.Fatalf("Multiply() = %v, %v, want %v", got, err, tt.want)
+			}
+		})
+	}
+}
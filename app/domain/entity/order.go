@@ -4,15 +4,37 @@ import (
 	"time"
 )
 
+var (
+	// ErrOrderNotFound is returned when an order does not exist
+	ErrOrderNotFound = NewError(ErrNotFound, "order not found")
+
+	// ErrInvalidOrder is returned when an order fails validation
+	ErrInvalidOrder = NewError(ErrValidation, "invalid order")
+
+	// ErrDuplicateOrder is returned when an order with the same id or orderId already exists
+	ErrDuplicateOrder = NewError(ErrConflict, "duplicate order")
+
+	// ErrOrderVersionConflict is returned when an order was changed since the version the caller read
+	ErrOrderVersionConflict = NewError(ErrConflict, "order version conflict")
+
+	// ErrOrderNotDeleted is returned when restoring or purging an order that is not deleted
+	ErrOrderNotDeleted = NewError(ErrConflict, "order is not deleted")
+)
+
 // Order represents an order in the system
 type Order struct {
-	ID         string    `json:"id"`
-	OrderID    string    `json:"orderId"`
-	CustomerID string    `json:"customerId"`
-	Status     string    `json:"status"`
-	CreatedAt  time.Time `json:"createdAt"`
-	UpdatedAt  time.Time `json:"updatedAt"`
-	DeletedAt  time.Time `json:"deletedAt,omitempty"`
+	ID            string      `json:"id"`
+	OrderID       string      `json:"orderId"`
+	CustomerID    string      `json:"customerId"`
+	CustomerName  string      `json:"customerName"`
+	CustomerEmail string      `json:"customerEmail"`
+	Status        string      `json:"status"`
+	Items         []OrderItem `json:"items"`
+	Total         Money       `json:"total"`
+	Version       int64       `json:"version"`
+	CreatedAt     time.Time   `json:"createdAt"`
+	UpdatedAt     time.Time   `json:"updatedAt"`
+	DeletedAt     *time.Time  `json:"deletedAt,omitempty"`
 }
 
 // OrderStatus represents the possible statuses of an order
@@ -0,0 +1,41 @@
+package entity
+
+import "errors"
+
+var (
+	// ErrInvalidBulkOperation is returned for a bulk operation with an unknown op or missing fields
+	ErrInvalidBulkOperation = NewError(ErrValidation, "invalid bulk operation")
+	// ErrBulkRolledBack is the error of bulk operations undone because another one failed
+	ErrBulkRolledBack = errors.New("rolled back because another operation failed")
+	// ErrBulkSkipped is the error of bulk operations not attempted because an earlier one failed
+	ErrBulkSkipped = errors.New("skipped because an earlier operation failed")
+)
+
+// BulkOrderOp represents the kinds of bulk order operations
+var BulkOrderOp = struct {
+	Create string
+	Update string
+	Delete string
+}{
+	Create: "create",
+	Update: "update",
+	Delete: "delete",
+}
+
+// BulkOrderOperation is one create, update or delete in a bulk request. Update replaces the
+// order named by ID with Order, at Order.Version when it is set.
+type BulkOrderOperation struct {
+	Op     string `json:"op"`
+	ID     string `json:"id"`
+	Order  *Order `json:"order"`
+	Reason string `json:"reason"`
+}
+
+// BulkOrderResult is the outcome of one bulk operation: the written order, or the error that
+// failed, rolled back or skipped it
+type BulkOrderResult struct {
+	Op    string
+	ID    string
+	Order *Order
+	Err   error
+}
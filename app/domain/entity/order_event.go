@@ -0,0 +1,82 @@
+package entity
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// OrderEventType represents the types of business events raised for orders
+var OrderEventType = struct {
+	Created       string
+	Updated       string
+	StatusChanged string
+	Deleted       string
+	Restored      string
+	Purged        string
+	Flagged       string
+}{
+	Created:       "OrderCreated",
+	Updated:       "OrderUpdated",
+	StatusChanged: "OrderStatusChanged",
+	Deleted:       "OrderDeleted",
+	Restored:      "OrderRestored",
+	Purged:        "OrderPurged",
+	Flagged:       "OrderFlagged",
+}
+
+// OrderEvent is a business event about an order. Order is the state of the order after
+// the event; status changes also carry the previous status and the reason.
+type OrderEvent struct {
+	ID         string    `json:"id"`
+	Type       string    `json:"type"`
+	OrderID    string    `json:"orderId"`
+	Actor      string    `json:"actor"`
+	OccurredAt time.Time `json:"occurredAt"`
+	Order      Order     `json:"order"`
+	FromStatus string    `json:"fromStatus,omitempty"`
+	ToStatus   string    `json:"toStatus,omitempty"`
+	Reason     string    `json:"reason,omitempty"`
+}
+
+// OutboxPosition is the place of an event in the outbox: the ID of the transaction that wrote
+// it and its sequence number. Once the transaction is older than every running one, no event
+// is written before it, so positions order the events of all replicas durably.
+type OutboxPosition struct {
+	TxID     int64
+	Sequence int64
+}
+
+// ParseOutboxPosition reads a position written by OutboxPosition.String
+func ParseOutboxPosition(value string) (OutboxPosition, bool) {
+	txID, sequence, found := strings.Cut(value, "-")
+	if !found {
+		return OutboxPosition{}, false
+	}
+	var position OutboxPosition
+	var err error
+	if position.TxID, err = strconv.ParseInt(txID, 10, 64); err != nil || position.TxID < 0 {
+		return OutboxPosition{}, false
+	}
+	if position.Sequence, err = strconv.ParseInt(sequence, 10, 64); err != nil || position.Sequence < 0 {
+		return OutboxPosition{}, false
+	}
+	return position, true
+}
+
+// String returns the position as "<txid>-<sequence>"
+func (p OutboxPosition) String() string {
+	return fmt.Sprintf("%d-%d", p.TxID, p.Sequence)
+}
+
+// After reports whether p comes after other in the outbox
+func (p OutboxPosition) After(other OutboxPosition) bool {
+	return p.TxID > other.TxID || (p.TxID == other.TxID && p.Sequence > other.Sequence)
+}
+
+// OutboxEntry is an order event read back from the outbox at its position
+type OutboxEntry struct {
+	Position OutboxPosition
+	Event    OrderEvent
+}
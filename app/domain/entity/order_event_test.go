@@ -0,0 +1,53 @@
+package entity
+
+import "testing"
+
+func TestParseOutboxPosition(t *testing.T) {
+	tests := []struct {
+		value  string
+		want   OutboxPosition
+		wantOK bool
+	}{
+		{"812-3", OutboxPosition{TxID: 812, Sequence: 3}, true},
+		{"0-0", OutboxPosition{}, true},
+		{"9223372036854775807-1", OutboxPosition{TxID: 9223372036854775807, Sequence: 1}, true},
+		{"", OutboxPosition{}, false},
+		{"812", OutboxPosition{}, false},
+		{"812-", OutboxPosition{}, false},
+		{"-3", OutboxPosition{}, false},
+		{"812--3", OutboxPosition{}, false},
+		{"812-3-1", OutboxPosition{}, false},
+		{"6f1c2a9e-42", OutboxPosition{}, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			got, ok := ParseOutboxPosition(tt.value)
+			if got != tt.want || ok != tt.wantOK {
+				t.Fatalf("ParseOutboxPosition(%q) = %v, %v, want %v, %v", tt.value, got, ok, tt.want, tt.wantOK)
+			}
+			if ok && got.String() != tt.value {
+				t.Errorf("String() = %q, want %q", got.String(), tt.value)
+			}
+		})
+	}
+}
+
+func TestOutboxPositionAfter(t *testing.T) {
+	tests := []struct {
+		p, other OutboxPosition
+		want     bool
+	}{
+		{OutboxPosition{TxID: 2, Sequence: 1}, OutboxPosition{TxID: 1, Sequence: 9}, true},
+		{OutboxPosition{TxID: 1, Sequence: 2}, OutboxPosition{TxID: 1, Sequence: 1}, true},
+		{OutboxPosition{TxID: 1, Sequence: 1}, OutboxPosition{TxID: 1, Sequence: 1}, false},
+		{OutboxPosition{TxID: 1, Sequence: 9}, OutboxPosition{TxID: 2, Sequence: 1}, false},
+		{OutboxPosition{TxID: 1, Sequence: 1}, OutboxPosition{}, true},
+	}
+
+	for _, tt := range tests {
+		if got := tt.p.After(tt.other); got != tt.want {
+			t.Errorf("%v.After(%v) = %v, want %v", tt.p, tt.other, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,13 @@
+package entity
+
+// ErrInvalidOrderItem is returned when an order item fails validation
+var ErrInvalidOrderItem = NewError(ErrValidation, "invalid order item")
+
+// OrderItem represents a line of an order
+type OrderItem struct {
+	ID        string `json:"id"`
+	SKU       string `json:"sku"`
+	Quantity  int    `json:"quantity"`
+	UnitPrice Money  `json:"unitPrice"`
+	LineTotal Money  `json:"lineTotal"`
+}
@@ -0,0 +1,48 @@
+package entity
+
+import (
+	"time"
+)
+
+// ErrInvalidOrderQuery is returned when an order query has an unknown filter, sort field or page
+var ErrInvalidOrderQuery = NewError(ErrValidation, "invalid order query")
+
+// OrderSortField represents the order fields orders can be sorted by
+var OrderSortField = struct {
+	ID         string
+	OrderID    string
+	CustomerID string
+	Status     string
+	CreatedAt  string
+	UpdatedAt  string
+}{
+	ID:         "id",
+	OrderID:    "orderId",
+	CustomerID: "customerId",
+	Status:     "status",
+	CreatedAt:  "createdAt",
+	UpdatedAt:  "updatedAt",
+}
+
+// OrderQuery selects a page of live orders. Empty filters match every order, and time ranges
+// include From and exclude To. Results are sorted by Sort, then by ID.
+type OrderQuery struct {
+	Statuses    []string
+	CustomerID  string
+	CreatedFrom *time.Time
+	CreatedTo   *time.Time
+	UpdatedFrom *time.Time
+	UpdatedTo   *time.Time
+	Sort        string
+	Descending  bool
+	Limit       int
+	Offset      int
+}
+
+// OrderPage is a page of orders with the number of orders matching the query
+type OrderPage struct {
+	Orders []Order `json:"orders"`
+	Total  int64   `json:"total"`
+	Limit  int     `json:"limit"`
+	Offset int     `json:"offset"`
+}
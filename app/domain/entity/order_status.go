@@ -0,0 +1,58 @@
+package entity
+
+import (
+	"slices"
+)
+
+var (
+	// ErrInvalidOrderStatus is returned for a status that is not one of OrderStatus
+	ErrInvalidOrderStatus = NewError(ErrValidation, "invalid order status")
+	// ErrInvalidStatusTransition is returned when an order cannot move from its current status to the requested one
+	ErrInvalidStatusTransition = NewError(ErrConflict, "invalid order status transition")
+)
+
+// initialOrderStatuses are the statuses an order may be created with
+var initialOrderStatuses = []string{
+	OrderStatus.New,
+	OrderStatus.Pending,
+}
+
+// orderTransitions lists the statuses an order may move to from each status.
+// Orders can be held or cancelled until they ship; RETURNED and CANCELLED are final.
+var orderTransitions = map[string][]string{
+	OrderStatus.New:         {OrderStatus.Pending, OrderStatus.Processing, OrderStatus.OnHold, OrderStatus.Cancelled},
+	OrderStatus.Pending:     {OrderStatus.Processing, OrderStatus.OnHold, OrderStatus.Cancelled},
+	OrderStatus.Processing:  {OrderStatus.Shipped, OrderStatus.Backordered, OrderStatus.OnHold, OrderStatus.Cancelled},
+	OrderStatus.Backordered: {OrderStatus.Processing, OrderStatus.OnHold, OrderStatus.Cancelled},
+	OrderStatus.OnHold:      {OrderStatus.Pending, OrderStatus.Processing, OrderStatus.Backordered, OrderStatus.Cancelled},
+	OrderStatus.Shipped:     {OrderStatus.Delivered, OrderStatus.Returned},
+	OrderStatus.Delivered:   {OrderStatus.Completed, OrderStatus.Returned},
+	OrderStatus.Completed:   {OrderStatus.Returned},
+	OrderStatus.Returned:    {},
+	OrderStatus.Cancelled:   {},
+}
+
+// IsValidOrderStatus reports whether status is a known order status
+func IsValidOrderStatus(status string) bool {
+	_, ok := orderTransitions[status]
+	return ok
+}
+
+// IsInitialOrderStatus reports whether an order may be created with status
+func IsInitialOrderStatus(status string) bool {
+	return slices.Contains(initialOrderStatuses, status)
+}
+
+// NextOrderStatuses returns the statuses an order may move to from status
+func NextOrderStatuses(status string) []string {
+	return append([]string{}, orderTransitions[status]...)
+}
+
+// CanTransitionOrderStatus reports whether an order may move from one status to another.
+// Keeping the current status is always allowed.
+func CanTransitionOrderStatus(from, to string) bool {
+	if from == to {
+		return IsValidOrderStatus(to)
+	}
+	return slices.Contains(orderTransitions[from], to)
+}
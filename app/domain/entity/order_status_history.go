@@ -0,0 +1,17 @@
+package entity
+
+import (
+	"time"
+)
+
+// OrderStatusChange records a status change of an order. FromStatus is empty for the
+// status an order was created with.
+type OrderStatusChange struct {
+	ID         string    `json:"id"`
+	OrderID    string    `json:"orderId"`
+	FromStatus string    `json:"fromStatus"`
+	ToStatus   string    `json:"toStatus"`
+	Actor      string    `json:"actor"`
+	Reason     string    `json:"reason"`
+	ChangedAt  time.Time `json:"changedAt"`
+}
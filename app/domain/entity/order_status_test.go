@@ -0,0 +1,105 @@
+package entity
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestCanTransitionOrderStatus(t *testing.T) {
+	tests := []struct {
+		from, to string
+		want     bool
+	}{
+		{OrderStatus.New, OrderStatus.Pending, true},
+		{OrderStatus.New, OrderStatus.Processing, true},
+		{OrderStatus.New, OrderStatus.OnHold, true},
+		{OrderStatus.New, OrderStatus.Cancelled, true},
+		{OrderStatus.Pending, OrderStatus.Processing, true},
+		{OrderStatus.Processing, OrderStatus.Shipped, true},
+		{OrderStatus.Processing, OrderStatus.Backordered, true},
+		{OrderStatus.Backordered, OrderStatus.Processing, true},
+		{OrderStatus.OnHold, OrderStatus.Pending, true},
+		{OrderStatus.Shipped, OrderStatus.Delivered, true},
+		{OrderStatus.Shipped, OrderStatus.Returned, true},
+		{OrderStatus.Delivered, OrderStatus.Completed, true},
+		{OrderStatus.Completed, OrderStatus.Returned, true},
+
+		// Keeping the current status is always allowed, even for final statuses
+		{OrderStatus.Processing, OrderStatus.Processing, true},
+		{OrderStatus.Cancelled, OrderStatus.Cancelled, true},
+
+		// Skipping steps or going back is not
+		{OrderStatus.New, OrderStatus.Shipped, false},
+		{OrderStatus.New, OrderStatus.Delivered, false},
+		{OrderStatus.Pending, OrderStatus.New, false},
+		{OrderStatus.Processing, OrderStatus.Pending, false},
+		{OrderStatus.Shipped, OrderStatus.Processing, false},
+		{OrderStatus.Shipped, OrderStatus.Cancelled, false},
+		{OrderStatus.Delivered, OrderStatus.Shipped, false},
+
+		// Final statuses have no way out
+		{OrderStatus.Cancelled, OrderStatus.New, false},
+		{OrderStatus.Cancelled, OrderStatus.Processing, false},
+		{OrderStatus.Returned, OrderStatus.Completed, false},
+
+		// Unknown statuses
+		{OrderStatus.New, "LOST", false},
+		{"LOST", OrderStatus.New, false},
+		{"LOST", "LOST", false},
+		{"", "", false},
+	}
+
+	for _, tt := range tests {
+		if got := CanTransitionOrderStatus(tt.from, tt.to); got != tt.want {
+			t.Errorf("CanTransitionOrderStatus(%q, %q) = %v, want %v", tt.from, tt.to, got, tt.want)
+		}
+	}
+}
+
+func TestOrderTransitionsAreClosed(t *testing.T) {
+	for from, targets := range orderTransitions {
+		for _, to := range targets {
+			if !IsValidOrderStatus(to) {
+				t.Errorf("%s may move to unknown status %s", from, to)
+			}
+			if to == from {
+				t.Errorf("%s lists itself as a transition", from)
+			}
+		}
+	}
+}
+
+func TestIsInitialOrderStatus(t *testing.T) {
+	tests := []struct {
+		status string
+		want   bool
+	}{
+		{OrderStatus.New, true},
+		{OrderStatus.Pending, true},
+		{OrderStatus.Processing, false},
+		{OrderStatus.Shipped, false},
+		{OrderStatus.Cancelled, false},
+		{"LOST", false},
+	}
+
+	for _, tt := range tests {
+		if got := IsInitialOrderStatus(tt.status); got != tt.want {
+			t.Errorf("IsInitialOrderStatus(%q) = %v, want %v", tt.status, got, tt.want)
+		}
+	}
+}
+
+func TestNextOrderStatusesReturnsACopy(t *testing.T) {
+	next := NextOrderStatuses(OrderStatus.New)
+	if !slices.Equal(next, orderTransitions[OrderStatus.New]) {
+		t.Fatalf("NextOrderStatuses(NEW) = %v, want %v", next, orderTransitions[OrderStatus.New])
+	}
+
+	next[0] = OrderStatus.Delivered
+	if orderTransitions[OrderStatus.New][0] == OrderStatus.Delivered {
+		t.Error("changing the result of NextOrderStatuses changed the transitions")
+	}
+	if got := NextOrderStatuses(OrderStatus.Cancelled); len(got) != 0 {
+		t.Errorf("NextOrderStatuses(CANCELLED) = %v, want none", got)
+	}
+}
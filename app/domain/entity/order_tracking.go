@@ -0,0 +1,51 @@
+package entity
+
+import (
+	"slices"
+)
+
+var (
+	// ErrInvalidTrackingToken is returned when a tracking token does not grant access to an order
+	ErrInvalidTrackingToken = NewError(ErrForbidden, "invalid tracking token")
+	// ErrTrackingTokenExpired is returned when a tracking token is past its expiry; the client
+	// needs a new token
+	ErrTrackingTokenExpired = NewError(ErrUnauthenticated, "tracking token expired")
+)
+
+// ChangedOrderFields returns the JSON names of the fields that differ between two states of
+// an order, in the order they appear in Order
+func ChangedOrderFields(before, after *Order) []string {
+	changed := []string{}
+	if before.OrderID != after.OrderID {
+		changed = append(changed, "orderId")
+	}
+	if before.CustomerID != after.CustomerID {
+		changed = append(changed, "customerId")
+	}
+	if before.CustomerName != after.CustomerName {
+		changed = append(changed, "customerName")
+	}
+	if before.CustomerEmail != after.CustomerEmail {
+		changed = append(changed, "customerEmail")
+	}
+	if before.Status != after.Status {
+		changed = append(changed, "status")
+	}
+	if !slices.Equal(before.Items, after.Items) {
+		changed = append(changed, "items")
+	}
+	if before.Total != after.Total {
+		changed = append(changed, "total")
+	}
+	if before.Version != after.Version {
+		changed = append(changed, "version")
+	}
+	if !before.UpdatedAt.Equal(after.UpdatedAt) {
+		changed = append(changed, "updatedAt")
+	}
+	if (before.DeletedAt == nil) != (after.DeletedAt == nil) ||
+		(before.DeletedAt != nil && !before.DeletedAt.Equal(*after.DeletedAt)) {
+		changed = append(changed, "deletedAt")
+	}
+	return changed
+}
@@ -0,0 +1,53 @@
+package entity
+
+var (
+	// ErrInvalidCredentials is returned for a bearer token that is malformed, badly signed or expired
+	ErrInvalidCredentials = NewError(ErrUnauthenticated, "invalid credentials")
+	// ErrAuthenticationRequired is returned when an anonymous caller uses a route that acts on
+	// behalf of someone
+	ErrAuthenticationRequired = NewError(ErrUnauthenticated, "authentication required")
+	// ErrOrderAccessDenied is returned when a principal acts on an order it may not access
+	ErrOrderAccessDenied = NewError(ErrForbidden, "order access denied")
+	// ErrAdminRequired is returned when a principal that is not an admin uses an admin route
+	ErrAdminRequired = NewError(ErrForbidden, "admin role required")
+)
+
+// PrincipalRole lists the roles a principal can have
+var PrincipalRole = struct {
+	Admin    string
+	Customer string
+}{
+	Admin:    "admin",
+	Customer: "customer",
+}
+
+// Principal is the authenticated caller of a request. The subject of a customer is their
+// customer ID.
+type Principal struct {
+	Subject string `json:"subject"`
+	Role    string `json:"role"`
+}
+
+// IsValidPrincipalRole reports whether role is a known principal role
+func IsValidPrincipalRole(role string) bool {
+	return role == PrincipalRole.Admin || role == PrincipalRole.Customer
+}
+
+// CanAccessOrder reports whether the principal may act on an order: admins may act on any
+// order, customers only on their own
+func (p *Principal) CanAccessOrder(order *Order) bool {
+	switch p.Role {
+	case PrincipalRole.Admin:
+		return true
+	case PrincipalRole.Customer:
+		return p.Subject != "" && p.Subject == order.CustomerID
+	default:
+		return false
+	}
+}
+
+// Scope identifies the principal across roles, so an admin and a customer with the same
+// subject never share state such as idempotency keys
+func (p *Principal) Scope() string {
+	return p.Role + ":" + p.Subject
+}
@@ -0,0 +1,24 @@
+package entity
+
+import (
+	"testing"
+)
+
+func TestPrincipalCanAccessOrder(t *testing.T) {
+	order := &Order{ID: "order-1", CustomerID: "511"}
+	tests := []struct {
+		principal Principal
+		want      bool
+	}{
+		{Principal{Subject: "ops", Role: PrincipalRole.Admin}, true},
+		{Principal{Subject: "511", Role: PrincipalRole.Customer}, true},
+		{Principal{Subject: "512", Role: PrincipalRole.Customer}, false},
+		{Principal{Subject: "511", Role: "root"}, false},
+	}
+
+	for _, tt := range tests {
+		if got := tt.principal.CanAccessOrder(order); got != tt.want {
+			t.Errorf("%+v CanAccessOrder() = %v, want %v", tt.principal, got, tt.want)
+		}
+	}
+}
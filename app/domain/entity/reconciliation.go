@@ -0,0 +1,35 @@
+package entity
+
+import (
+	"time"
+)
+
+// ReconciliationReport represents the outcome of comparing orders in PostgreSQL with the search index
+type ReconciliationReport struct {
+	ID          string     `json:"id"`
+	Status      string     `json:"status"`
+	Repair      bool       `json:"repair"`
+	Scanned     int        `json:"scanned"`
+	Matching    int        `json:"matching"`
+	Missing     int        `json:"missing"`
+	Stale       int        `json:"stale"`
+	Orphaned    int        `json:"orphaned"`
+	Repaired    int        `json:"repaired"`
+	MissingIDs  []string   `json:"missingIds,omitempty"`
+	StaleIDs    []string   `json:"staleIds,omitempty"`
+	OrphanedIDs []string   `json:"orphanedIds,omitempty"`
+	Error       string     `json:"error,omitempty"`
+	StartedAt   time.Time  `json:"startedAt"`
+	FinishedAt  *time.Time `json:"finishedAt,omitempty"`
+}
+
+// ReconciliationStatus represents the possible statuses of a reconciliation run
+var ReconciliationStatus = struct {
+	Running   string
+	Completed string
+	Failed    string
+}{
+	Running:   "RUNNING",
+	Completed: "COMPLETED",
+	Failed:    "FAILED",
+}
@@ -0,0 +1,16 @@
+package entity
+
+// ReplicationSlot represents a PostgreSQL replication slot and the WAL it retains
+type ReplicationSlot struct {
+	Name              string `json:"name"`
+	Plugin            string `json:"plugin"`
+	SlotType          string `json:"slotType"`
+	Database          string `json:"database"`
+	Active            bool   `json:"active"`
+	ActivePID         *int   `json:"activePid,omitempty"`
+	RestartLSN        string `json:"restartLsn"`
+	ConfirmedFlushLSN string `json:"confirmedFlushLsn"`
+	RetainedWALBytes  int64  `json:"retainedWalBytes"`
+	WALStatus         string `json:"walStatus"`
+	Alert             bool   `json:"alert"`
+}
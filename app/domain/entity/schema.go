@@ -0,0 +1,39 @@
+package entity
+
+// Column represents a column of a source table
+type Column struct {
+	Name     string `json:"name"`
+	DataType string `json:"dataType"`
+}
+
+// SchemaChange represents a difference between a source table and its search mapping
+type SchemaChange struct {
+	Field       string `json:"field"`
+	ColumnType  string `json:"columnType,omitempty"`
+	MappingType string `json:"mappingType,omitempty"`
+	Kind        string `json:"kind"`
+	Detail      string `json:"detail"`
+}
+
+// SchemaChangeKind represents the possible kinds of schema changes
+var SchemaChangeKind = struct {
+	Additive string
+	Reindex  string
+	Removed  string
+	Unknown  string
+}{
+	Additive: "ADDITIVE",
+	Reindex:  "REINDEX",
+	Removed:  "REMOVED",
+	Unknown:  "UNKNOWN",
+}
+
+// SchemaPlan lists the changes needed to bring a search mapping in line with its source table
+type SchemaPlan struct {
+	Table        string         `json:"table"`
+	Index        string         `json:"index"`
+	IndexExists  bool           `json:"indexExists"`
+	Changes      []SchemaChange `json:"changes"`
+	JSONFields   []string       `json:"jsonFields,omitempty"`
+	NeedsReindex bool           `json:"needsReindex"`
+}
@@ -0,0 +1,86 @@
+package entity
+
+import (
+	"time"
+)
+
+// Signal represents a row in the Debezium signalling table
+type Signal struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Data      string    `json:"data"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// SignalType represents the Debezium signal types used by the application
+var SignalType = struct {
+	ExecuteSnapshot     string
+	SnapshotWindowOpen  string
+	SnapshotWindowClose string
+}{
+	ExecuteSnapshot:     "execute-snapshot",
+	SnapshotWindowOpen:  "snapshot-window-open",
+	SnapshotWindowClose: "snapshot-window-close",
+}
+
+// SnapshotCondition restricts the snapshot of a data collection to the rows whose Column
+// compares to Values with Operator. Conditions on the same data collection are combined with AND.
+type SnapshotCondition struct {
+	DataCollection string   `json:"dataCollection"`
+	Column         string   `json:"column"`
+	Operator       string   `json:"operator"`
+	Values         []string `json:"values"`
+}
+
+// SnapshotOperator represents the comparison operators of snapshot conditions
+var SnapshotOperator = struct {
+	Equal          string
+	NotEqual       string
+	Less           string
+	LessOrEqual    string
+	Greater        string
+	GreaterOrEqual string
+	In             string
+}{
+	Equal:          "eq",
+	NotEqual:       "ne",
+	Less:           "lt",
+	LessOrEqual:    "lte",
+	Greater:        "gt",
+	GreaterOrEqual: "gte",
+	In:             "in",
+}
+
+// SnapshotRequest represents an ad-hoc incremental snapshot requested through the API
+type SnapshotRequest struct {
+	ID              string              `json:"id"`
+	DataCollections []string            `json:"dataCollections"`
+	Conditions      []SnapshotCondition `json:"conditions,omitempty"`
+	Status          string              `json:"status"`
+	ChunksCompleted int                 `json:"chunksCompleted"`
+	SignalledAt     *time.Time          `json:"signalledAt,omitempty"`
+	StartedAt       *time.Time          `json:"startedAt,omitempty"`
+	LastChunkAt     *time.Time          `json:"lastChunkAt,omitempty"`
+	CompletedAt     *time.Time          `json:"completedAt,omitempty"`
+	CreatedAt       time.Time           `json:"createdAt"`
+	UpdatedAt       time.Time           `json:"updatedAt"`
+}
+
+// SnapshotStatus represents the possible statuses of a snapshot request
+var SnapshotStatus = struct {
+	Pending   string
+	Running   string
+	Completed string
+	TimedOut  string
+}{
+	Pending:   "PENDING",
+	Running:   "RUNNING",
+	Completed: "COMPLETED",
+	TimedOut:  "TIMED_OUT",
+}
+
+// IsFinishedSnapshotStatus reports whether a snapshot request with the status is no longer
+// in progress, whether it completed or never started
+func IsFinishedSnapshotStatus(status string) bool {
+	return status == SnapshotStatus.Completed || status == SnapshotStatus.TimedOut
+}
@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// CustomerRepository defines the interface for customer data access
+type CustomerRepository interface {
+	// FindAll retrieves all customers
+	FindAll(ctx context.Context) ([]entity.Customer, error)
+
+	// FindByID retrieves a customer by its ID
+	FindByID(ctx context.Context, id string) (*entity.Customer, error)
+
+	// FindByIDForShare retrieves a customer by its ID and locks it against updates and deletes
+	// until the transaction carried by ctx ends
+	FindByIDForShare(ctx context.Context, id string) (*entity.Customer, error)
+
+	// FindByIDs retrieves the customers with the given IDs, keyed by ID; missing ids are absent
+	FindByIDs(ctx context.Context, ids []string) (map[string]entity.Customer, error)
+
+	// HasOrders reports whether any order, including soft-deleted ones, references the customer
+	HasOrders(ctx context.Context, id string) (bool, error)
+
+	// Create creates a new customer
+	Create(ctx context.Context, customer *entity.Customer) error
+
+	// Update updates an existing customer. The details copied onto its orders are left to
+	// OrderRepository.UpdateCustomerDetails, so that they are written with their events.
+	Update(ctx context.Context, customer *entity.Customer) error
+
+	// Delete deletes a customer by its ID
+	Delete(ctx context.Context, id string) error
+}
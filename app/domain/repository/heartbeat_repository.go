@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// HeartbeatRepository defines the interface for writing heartbeats to the source database
+type HeartbeatRepository interface {
+	// Upsert creates or replaces the heartbeat row
+	Upsert(ctx context.Context, heartbeat *entity.Heartbeat) error
+}
+
+// HeartbeatSearchRepository defines the interface for reading replicated heartbeats from the search index
+type HeartbeatSearchRepository interface {
+	// FindByID retrieves the replicated heartbeat by its ID
+	FindByID(ctx context.Context, id string) (*entity.Heartbeat, error)
+}
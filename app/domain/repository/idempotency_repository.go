@@ -0,0 +1,25 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// IdempotencyRepository defines the interface for idempotency key persistence
+type IdempotencyRepository interface {
+	// Reserve stores record unless its key is already held by an unexpired record of the same
+	// scope, which is returned instead. A held record of the same request that did not complete
+	// within its lease is taken over. It returns nil when record was stored or took over the key.
+	Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error)
+
+	// Complete stores the response of a reserved record
+	Complete(ctx context.Context, record *entity.IdempotencyRecord) error
+
+	// Release deletes a reserved record that did not complete, so its key can be retried
+	Release(ctx context.Context, scope, key string) error
+
+	// DeleteExpired deletes records that expired before the given time and returns how many were deleted
+	DeleteExpired(ctx context.Context, before time.Time) (int64, error)
+}
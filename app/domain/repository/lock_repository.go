@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+)
+
+// LockRepository defines the interface for locks shared by all replicas of the app
+type LockRepository interface {
+	// TryLock takes the named lock without waiting. When acquired is true, release must be
+	// called to give the lock up.
+	TryLock(ctx context.Context, name string) (release func(), acquired bool, err error)
+}
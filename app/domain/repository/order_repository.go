@@ -2,6 +2,7 @@ package repository
 
 import (
 	"context"
+	"time"
 
 	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
 )
@@ -11,18 +12,76 @@ type OrderRepository interface {
 	// FindAll retrieves all orders
 	FindAll(ctx context.Context) ([]entity.Order, error)
 
+	// Find retrieves the page of orders selected by query and the number of orders matching it
+	Find(ctx context.Context, query entity.OrderQuery) ([]entity.Order, int64, error)
+
 	// FindByID retrieves an order by its ID
 	FindByID(ctx context.Context, id string) (*entity.Order, error)
 
 	// FindByStatus retrieves orders by status
 	FindByStatus(ctx context.Context, status string) ([]entity.Order, error)
 
-	// Create creates a new order
-	Create(ctx context.Context, order *entity.Order) error
+	// FindDeleted retrieves all soft-deleted orders
+	FindDeleted(ctx context.Context) ([]entity.Order, error)
+
+	// FindDeletedByID retrieves a soft-deleted order by its ID
+	FindDeletedByID(ctx context.Context, id string) (*entity.Order, error)
+
+	// FindDeletedBefore retrieves up to limit orders soft-deleted before the given time
+	FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]entity.Order, error)
+
+	// FindInStatusBefore retrieves up to limit orders that entered status before the given
+	// time, leaving out orders already flagged by unflaggedRule when it is not empty
+	FindInStatusBefore(ctx context.Context, status string, before time.Time, unflaggedRule string, limit int) ([]entity.Order, error)
+
+	// FindAfterID retrieves up to limit orders, including soft-deleted ones, with IDs greater than afterID ordered by ID
+	FindAfterID(ctx context.Context, afterID string, limit int) ([]entity.Order, error)
+
+	// FindWithStaleCustomer retrieves the orders of a customer, including soft-deleted ones,
+	// whose copied name or email differ from the customer's, locking them for update
+	FindWithStaleCustomer(ctx context.Context, customer *entity.Customer) ([]entity.Order, error)
+
+	// FindExistingIDs returns the subset of ids that exist, including soft-deleted orders
+	FindExistingIDs(ctx context.Context, ids []string) ([]string, error)
+
+	// FindStatusHistory retrieves the status changes of an order, oldest first
+	FindStatusHistory(ctx context.Context, orderID string) ([]entity.OrderStatusChange, error)
+
+	// FindStatusHistoryByOrderIDs retrieves the status changes of the given orders, keyed by
+	// order ID and oldest first; orders without changes are absent
+	FindStatusHistoryByOrderIDs(ctx context.Context, orderIDs []string) (map[string][]entity.OrderStatusChange, error)
+
+	// FindFlags retrieves the flags of an order, oldest first
+	FindFlags(ctx context.Context, orderID string) ([]entity.OrderFlag, error)
+
+	// FindFlagsByOrderIDs retrieves the flags of the given orders, keyed by order ID and oldest
+	// first; orders without flags are absent
+	FindFlagsByOrderIDs(ctx context.Context, orderIDs []string) (map[string][]entity.OrderFlag, error)
+
+	// Flag flags an order unless it is already flagged by the same rule, writing events to the
+	// outbox only when the flag is new; created reports whether it was
+	Flag(ctx context.Context, flag *entity.OrderFlag, events []entity.OrderEvent) (created bool, err error)
+
+	// NextOrderNumber returns the next value of the order number sequence
+	NextOrderNumber(ctx context.Context) (int64, error)
+
+	// Create creates a new order, recording its initial status and writing events to the outbox
+	Create(ctx context.Context, order *entity.Order, change *entity.OrderStatusChange, events []entity.OrderEvent) error
+
+	// Update updates an existing order, recording the status change when change is not nil
+	// and writing events to the outbox
+	Update(ctx context.Context, order *entity.Order, change *entity.OrderStatusChange, events []entity.OrderEvent) error
+
+	// UpdateCustomerDetails writes the customer name and email of an order at its version,
+	// including a soft-deleted one, and writes events to the outbox
+	UpdateCustomerDetails(ctx context.Context, order *entity.Order, events []entity.OrderEvent) error
+
+	// Delete soft-deletes an order at its version and writes events to the outbox
+	Delete(ctx context.Context, order *entity.Order, events []entity.OrderEvent) error
 
-	// Update updates an existing order
-	Update(ctx context.Context, order *entity.Order) error
+	// Restore undeletes a soft-deleted order at its version and writes events to the outbox
+	Restore(ctx context.Context, order *entity.Order, events []entity.OrderEvent) error
 
-	// Delete deletes an order by its ID
-	Delete(ctx context.Context, id string) error
+	// Purge permanently removes a soft-deleted order and its items and writes events to the outbox
+	Purge(ctx context.Context, id string, events []entity.OrderEvent) error
 }
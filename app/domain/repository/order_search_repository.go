@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// OrderSearchRepository defines the interface for order documents in the search index
+type OrderSearchRepository interface {
+	// Search retrieves the page of indexed live orders selected by query and the number of
+	// orders matching it
+	Search(ctx context.Context, query entity.OrderQuery) ([]entity.Order, int64, error)
+
+	// FindByIDs retrieves the indexed orders for the given ids, keyed by ID; missing ids are absent
+	FindByIDs(ctx context.Context, ids []string) (map[string]entity.Order, error)
+
+	// ScanIDs walks the IDs of all indexed orders in batches of at most batchSize
+	ScanIDs(ctx context.Context, batchSize int, fn func(ids []string) error) error
+
+	// DeleteByIDs removes the indexed orders with the given ids
+	DeleteByIDs(ctx context.Context, ids []string) error
+}
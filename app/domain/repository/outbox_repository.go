@@ -0,0 +1,23 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// OutboxRepository defines the interface for maintaining and reading the event outbox.
+// Events are written by OrderRepository in the transaction of the order change.
+type OutboxRepository interface {
+	// DeleteBefore deletes events that occurred before the given time and returns how many were deleted
+	DeleteBefore(ctx context.Context, before time.Time) (int64, error)
+	// FindAfter returns up to limit order events after a position, in outbox order. Only events
+	// whose position is final are returned: those of transactions older than every running one.
+	FindAfter(ctx context.Context, after entity.OutboxPosition, limit int) ([]entity.OutboxEntry, error)
+	// Head returns the position of the last event FindAfter can return, or the zero position
+	// when the outbox is empty
+	Head(ctx context.Context) (entity.OutboxPosition, error)
+	// Exists reports whether the outbox still holds the event at a position
+	Exists(ctx context.Context, position entity.OutboxPosition) (bool, error)
+}
@@ -0,0 +1,22 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// ReconciliationRepository defines the interface for reconciliation report data access
+type ReconciliationRepository interface {
+	// FindAll retrieves all reconciliation reports, newest first
+	FindAll(ctx context.Context) ([]entity.ReconciliationReport, error)
+
+	// FindByID retrieves a reconciliation report by its ID
+	FindByID(ctx context.Context, id string) (*entity.ReconciliationReport, error)
+
+	// Create creates a new reconciliation report
+	Create(ctx context.Context, report *entity.ReconciliationReport) error
+
+	// Update updates an existing reconciliation report
+	Update(ctx context.Context, report *entity.ReconciliationReport) error
+}
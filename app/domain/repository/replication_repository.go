@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// ReplicationRepository defines the interface for inspecting and managing replication slots
+type ReplicationRepository interface {
+	// FindSlots retrieves all replication slots
+	FindSlots(ctx context.Context) ([]entity.ReplicationSlot, error)
+
+	// FindSlotByName retrieves a replication slot by its name
+	FindSlotByName(ctx context.Context, name string) (*entity.ReplicationSlot, error)
+
+	// DropSlot drops a replication slot by its name
+	DropSlot(ctx context.Context, name string) error
+}
@@ -0,0 +1,28 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// SchemaRepository defines the interface for reading source table definitions
+type SchemaRepository interface {
+	// FindColumns retrieves the columns of a table in ordinal order
+	FindColumns(ctx context.Context, table string) ([]entity.Column, error)
+}
+
+// MappingRepository defines the interface for managing a search index mapping
+type MappingRepository interface {
+	// FindFieldTypes retrieves the top-level field types of the index; exists is false when the index is missing
+	FindFieldTypes(ctx context.Context, index string) (types map[string]string, exists bool, err error)
+
+	// CreateIndex creates the index with the given field types
+	CreateIndex(ctx context.Context, index string, types map[string]string) error
+
+	// AddFields adds new fields with the given types to the index mapping
+	AddFields(ctx context.Context, index string, types map[string]string) error
+
+	// EnsureJSONPipeline makes the index parse the given JSON string fields on ingest
+	EnsureJSONPipeline(ctx context.Context, index string, fields []string) error
+}
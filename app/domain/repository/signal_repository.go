@@ -0,0 +1,36 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// SignalRepository defines the interface for Debezium signal and snapshot request data access
+type SignalRepository interface {
+	// CreateSnapshotRequest stores a new snapshot request
+	CreateSnapshotRequest(ctx context.Context, request *entity.SnapshotRequest) error
+
+	// SignalSnapshotRequest updates a snapshot request and writes its signal in one transaction
+	SignalSnapshotRequest(ctx context.Context, request *entity.SnapshotRequest, signal *entity.Signal) error
+
+	// FindSnapshotRequestByID retrieves a snapshot request by its ID
+	FindSnapshotRequestByID(ctx context.Context, id string) (*entity.SnapshotRequest, error)
+
+	// FindAllSnapshotRequests retrieves all snapshot requests, newest first
+	FindAllSnapshotRequests(ctx context.Context) ([]entity.SnapshotRequest, error)
+
+	// FindSnapshotRequestsByStatus retrieves snapshot requests having one of the given statuses
+	FindSnapshotRequestsByStatus(ctx context.Context, statuses ...string) ([]entity.SnapshotRequest, error)
+
+	// UpdateSnapshotRequest updates an existing snapshot request
+	UpdateSnapshotRequest(ctx context.Context, request *entity.SnapshotRequest) error
+
+	// FindSignalsAfter retrieves signals of the given types written after the signal with the
+	// given ID, oldest first; none when that signal does not exist
+	FindSignalsAfter(ctx context.Context, signalID string, types ...string) ([]entity.Signal, error)
+
+	// DeleteSignalsBefore deletes signals written before the given time
+	DeleteSignalsBefore(ctx context.Context, before time.Time) error
+}
@@ -0,0 +1,10 @@
+package repository
+
+import "context"
+
+// Transactor runs several repository calls in one database transaction
+type Transactor interface {
+	// InTransaction calls fn with a context carrying a transaction, which repositories given
+	// that context join. The transaction commits when fn returns nil and rolls back otherwise.
+	InTransaction(ctx context.Context, fn func(ctx context.Context) error) error
+}
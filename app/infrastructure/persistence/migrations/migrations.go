@@ -2,6 +2,7 @@ package migrations
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
 	"gorm.io/gorm"
@@ -11,11 +12,134 @@ import (
 func RunMigrations(db *gorm.DB) error {
 	fmt.Println("Running database migrations...")
 
+	// Customers come first so orders can reference them
+	if err := db.AutoMigrate(&models.Customer{}); err != nil {
+		return fmt.Errorf("failed to migrate customers table: %w", err)
+	}
+	if err := backfillCustomers(db); err != nil {
+		return fmt.Errorf("failed to backfill customers: %w", err)
+	}
+
+	// Order IDs must be unique before their unique index can be created
+	if err := checkDuplicateOrderIDs(db); err != nil {
+		return err
+	}
+
 	// Auto migrate the models
-	if err := db.AutoMigrate(&models.Order{}); err != nil {
+	if err := db.AutoMigrate(&models.Order{}, &models.OrderItem{}); err != nil {
 		return fmt.Errorf("failed to migrate database: %w", err)
 	}
+	if err := backfillOrderCustomers(db); err != nil {
+		return fmt.Errorf("failed to backfill order customers: %w", err)
+	}
+	if err := db.Exec("CREATE SEQUENCE IF NOT EXISTS " + models.OrderNumberSequence).Error; err != nil {
+		return fmt.Errorf("failed to create order number sequence: %w", err)
+	}
+
+	// Audit trail of order status changes
+	if err := db.AutoMigrate(&models.OrderStatusHistory{}); err != nil {
+		return fmt.Errorf("failed to migrate order status history table: %w", err)
+	}
+
+	// Orders flagged by automation rules
+	if err := db.AutoMigrate(&models.OrderFlag{}); err != nil {
+		return fmt.Errorf("failed to migrate order flags table: %w", err)
+	}
+
+	// Outbox of order events, published by the outbox event router
+	if err := db.AutoMigrate(&models.OutboxEvent{}); err != nil {
+		return fmt.Errorf("failed to migrate outbox table: %w", err)
+	}
+	if err := db.Exec(outboxPositionColumns).Error; err != nil {
+		return fmt.Errorf("failed to add outbox positions: %w", err)
+	}
+	if err := db.Exec(`CREATE INDEX IF NOT EXISTS idx_outbox_position ON outbox (txid, "sequence")`).Error; err != nil {
+		return fmt.Errorf("failed to index outbox positions: %w", err)
+	}
+
+	// Idempotency keys and the responses replayed for them
+	if err := renameIdempotencyScope(db); err != nil {
+		return fmt.Errorf("failed to rename idempotency key scopes: %w", err)
+	}
+	if err := db.AutoMigrate(&models.IdempotencyKey{}); err != nil {
+		return fmt.Errorf("failed to migrate idempotency keys table: %w", err)
+	}
+
+	// Debezium signalling table and the snapshot requests sent through it
+	if err := db.AutoMigrate(&models.DebeziumSignal{}, &models.SnapshotRequest{}); err != nil {
+		return fmt.Errorf("failed to migrate signalling tables: %w", err)
+	}
+
+	// Heartbeat table used to measure replication latency
+	if err := db.AutoMigrate(&models.Heartbeat{}); err != nil {
+		return fmt.Errorf("failed to migrate heartbeat table: %w", err)
+	}
+
+	// Reports written by the PostgreSQL to Elasticsearch reconciler
+	if err := db.AutoMigrate(&models.ReconciliationReport{}); err != nil {
+		return fmt.Errorf("failed to migrate reconciliation table: %w", err)
+	}
 
 	fmt.Println("Database migration completed")
 	return nil
 }
+
+// outboxPositionColumns adds the position of each outbox event: the ID of the transaction
+// that wrote it and a sequence number. Events already in the outbox get the transaction of
+// the migration and are numbered in table order.
+const outboxPositionColumns = `ALTER TABLE outbox
+	ADD COLUMN IF NOT EXISTS txid bigint NOT NULL DEFAULT txid_current(),
+	ADD COLUMN IF NOT EXISTS "sequence" bigserial`
+
+// backfillCustomers creates a placeholder customer for every customer ID used by existing
+// orders, so the foreign key from orders to customers can be added
+func backfillCustomers(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Order{}) {
+		return nil
+	}
+
+	return db.Exec(`INSERT INTO customers (id, name, email, phone, created_at, updated_at)
+		SELECT DISTINCT o.customer_id, o.customer_id, '', '', now(), now()
+		FROM orders o
+		WHERE o.customer_id IS NOT NULL
+		ON CONFLICT (id) DO NOTHING`).Error
+}
+
+// checkDuplicateOrderIDs fails with the duplicated order IDs, including those of deleted
+// orders, when the unique index on order_id is still missing and could not be created
+func checkDuplicateOrderIDs(db *gorm.DB) error {
+	if !db.Migrator().HasTable(&models.Order{}) || db.Migrator().HasIndex(&models.Order{}, "idx_orders_order_id") {
+		return nil
+	}
+
+	var duplicates []string
+	if err := db.Raw(`SELECT order_id FROM orders
+		GROUP BY order_id HAVING count(*) > 1
+		ORDER BY order_id LIMIT 10`).Scan(&duplicates).Error; err != nil {
+		return fmt.Errorf("failed to look up duplicate order IDs: %w", err)
+	}
+	if len(duplicates) > 0 {
+		return fmt.Errorf("orders share order IDs %s; make them unique before migrating", strings.Join(duplicates, ", "))
+	}
+	return nil
+}
+
+// renameIdempotencyScope renames the actor column of idempotency keys, which holds their
+// scope, to scope. The primary key follows the renamed column.
+func renameIdempotencyScope(db *gorm.DB) error {
+	migrator := db.Migrator()
+	if !migrator.HasTable(&models.IdempotencyKey{}) || !migrator.HasColumn(&models.IdempotencyKey{}, "actor") {
+		return nil
+	}
+	return migrator.RenameColumn(&models.IdempotencyKey{}, "actor", "scope")
+}
+
+// backfillOrderCustomers copies customer names and emails onto orders that predate them. The
+// orders get a new version, so updates still working from the old details conflict.
+func backfillOrderCustomers(db *gorm.DB) error {
+	return db.Exec(`UPDATE orders o
+		SET customer_name = c.name, customer_email = c.email, version = o.version + 1, updated_at = now()
+		FROM customers c
+		WHERE c.id = o.customer_id
+		AND (o.customer_name IS DISTINCT FROM c.name OR o.customer_email IS DISTINCT FROM c.email)`).Error
+}
@@ -0,0 +1,91 @@
+package migrations
+
+import (
+	"fmt"
+	"strings"
+
+	"gorm.io/gorm"
+)
+
+// EnsurePublication creates the publication for exactly the given tables, or resets the
+// table list of an existing one, and sets the replica identity of every table. Tables
+// without a primary key always get REPLICA IDENTITY FULL so updates and deletes carry a key.
+func EnsurePublication(db *gorm.DB, publication string, tables []string, replicaIdentity string) error {
+	fmt.Printf("Ensuring publication %s for %s...\n", publication, strings.Join(tables, ", "))
+
+	replicaIdentity = strings.ToUpper(replicaIdentity)
+	if replicaIdentity != "DEFAULT" && replicaIdentity != "FULL" {
+		return fmt.Errorf("unsupported replica identity %q, expected DEFAULT or FULL", replicaIdentity)
+	}
+	if len(tables) == 0 {
+		return fmt.Errorf("publication %s has no tables configured", publication)
+	}
+
+	quotedTables := make([]string, len(tables))
+	for i, table := range tables {
+		quotedTables[i] = quoteTable(table)
+	}
+
+	var count int64
+	if err := db.Raw("SELECT count(*) FROM pg_publication WHERE pubname = ?", publication).Scan(&count).Error; err != nil {
+		return fmt.Errorf("failed to look up publication: %w", err)
+	}
+
+	statement := "CREATE PUBLICATION %s FOR TABLE %s"
+	if count > 0 {
+		statement = "ALTER PUBLICATION %s SET TABLE %s"
+	}
+	if err := db.Exec(fmt.Sprintf(statement, quoteIdent(publication), strings.Join(quotedTables, ", "))).Error; err != nil {
+		return fmt.Errorf("failed to configure publication %s: %w", publication, err)
+	}
+
+	for i, table := range tables {
+		if err := ensureReplicaIdentity(db, table, quotedTables[i], replicaIdentity); err != nil {
+			return err
+		}
+	}
+
+	fmt.Println("Publication configured")
+	return nil
+}
+
+// ensureReplicaIdentity sets the replica identity of a table when it differs from the wanted one
+func ensureReplicaIdentity(db *gorm.DB, table, quotedTable, replicaIdentity string) error {
+	var current struct {
+		Identity   string
+		PrimaryKey bool
+	}
+	if err := db.Raw(`SELECT c.relreplident AS identity,
+		EXISTS (SELECT 1 FROM pg_index i WHERE i.indrelid = c.oid AND i.indisprimary) AS primary_key
+		FROM pg_class c WHERE c.oid = ?::regclass`, table).Scan(&current).Error; err != nil {
+		return fmt.Errorf("failed to inspect table %s: %w", table, err)
+	}
+
+	wanted := replicaIdentity
+	if !current.PrimaryKey {
+		wanted = "FULL"
+	}
+	if (wanted == "FULL" && current.Identity == "f") || (wanted == "DEFAULT" && current.Identity == "d") {
+		return nil
+	}
+
+	if err := db.Exec(fmt.Sprintf("ALTER TABLE %s REPLICA IDENTITY %s", quotedTable, wanted)).Error; err != nil {
+		return fmt.Errorf("failed to set replica identity of %s: %w", table, err)
+	}
+	fmt.Printf("Set replica identity of %s to %s\n", table, wanted)
+	return nil
+}
+
+// quoteTable quotes a schema-qualified table name, defaulting to the public schema
+func quoteTable(table string) string {
+	schema, name, found := strings.Cut(table, ".")
+	if !found {
+		schema, name = "public", table
+	}
+	return quoteIdent(schema) + "." + quoteIdent(name)
+}
+
+// quoteIdent quotes a PostgreSQL identifier
+func quoteIdent(identifier string) string {
+	return `"` + strings.ReplaceAll(identifier, `"`, `""`) + `"`
+}
@@ -0,0 +1,44 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// Customer represents the database model for a customer
+type Customer struct {
+	ID        string `gorm:"primaryKey"`
+	Name      string `gorm:"not null"`
+	Email     string `gorm:"index"`
+	Phone     string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+// TableName specifies the table name for the Customer model
+func (Customer) TableName() string {
+	return "customers"
+}
+
+// ToEntity converts the model to a domain entity
+func (c *Customer) ToEntity() *entity.Customer {
+	return &entity.Customer{
+		ID:        c.ID,
+		Name:      c.Name,
+		Email:     c.Email,
+		Phone:     c.Phone,
+		CreatedAt: c.CreatedAt,
+		UpdatedAt: c.UpdatedAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (c *Customer) FromEntity(customer *entity.Customer) {
+	c.ID = customer.ID
+	c.Name = customer.Name
+	c.Email = customer.Email
+	c.Phone = customer.Phone
+	c.CreatedAt = customer.CreatedAt
+	c.UpdatedAt = customer.UpdatedAt
+}
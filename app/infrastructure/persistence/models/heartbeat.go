@@ -0,0 +1,35 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// Heartbeat represents the database model for a replication heartbeat
+type Heartbeat struct {
+	ID     string `gorm:"primaryKey"`
+	Seq    int64  `gorm:"not null"`
+	BeatAt time.Time
+}
+
+// TableName specifies the table name for the Heartbeat model
+func (Heartbeat) TableName() string {
+	return "heartbeats"
+}
+
+// ToEntity converts the model to a domain entity
+func (h *Heartbeat) ToEntity() *entity.Heartbeat {
+	return &entity.Heartbeat{
+		ID:     h.ID,
+		Seq:    h.Seq,
+		BeatAt: h.BeatAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (h *Heartbeat) FromEntity(heartbeat *entity.Heartbeat) {
+	h.ID = heartbeat.ID
+	h.Seq = heartbeat.Seq
+	h.BeatAt = heartbeat.BeatAt
+}
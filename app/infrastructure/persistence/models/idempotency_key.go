@@ -0,0 +1,59 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// IdempotencyKey represents the database model for a request made with an idempotency key
+type IdempotencyKey struct {
+	Scope          string    `gorm:"primaryKey"`
+	Key            string    `gorm:"primaryKey"`
+	RequestHash    string    `gorm:"not null"`
+	Completed      bool      `gorm:"not null;default:false"`
+	StatusCode     int       `gorm:"not null;default:0"`
+	ContentType    string    `gorm:"not null;default:''"`
+	ETag           string    `gorm:"column:etag;not null;default:''"`
+	Body           []byte    `gorm:"type:bytea"`
+	CreatedAt      time.Time `gorm:"not null"`
+	LeaseExpiresAt time.Time `gorm:"not null;default:CURRENT_TIMESTAMP"`
+	ExpiresAt      time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for the IdempotencyKey model
+func (IdempotencyKey) TableName() string {
+	return "idempotency_keys"
+}
+
+// ToEntity converts the model to a domain entity
+func (k *IdempotencyKey) ToEntity() *entity.IdempotencyRecord {
+	return &entity.IdempotencyRecord{
+		Scope:          k.Scope,
+		Key:            k.Key,
+		RequestHash:    k.RequestHash,
+		Completed:      k.Completed,
+		StatusCode:     k.StatusCode,
+		ContentType:    k.ContentType,
+		ETag:           k.ETag,
+		Body:           k.Body,
+		CreatedAt:      k.CreatedAt,
+		LeaseExpiresAt: k.LeaseExpiresAt,
+		ExpiresAt:      k.ExpiresAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (k *IdempotencyKey) FromEntity(record *entity.IdempotencyRecord) {
+	k.Scope = record.Scope
+	k.Key = record.Key
+	k.RequestHash = record.RequestHash
+	k.Completed = record.Completed
+	k.StatusCode = record.StatusCode
+	k.ContentType = record.ContentType
+	k.ETag = record.ETag
+	k.Body = record.Body
+	k.CreatedAt = record.CreatedAt
+	k.LeaseExpiresAt = record.LeaseExpiresAt
+	k.ExpiresAt = record.ExpiresAt
+}
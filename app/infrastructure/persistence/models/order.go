@@ -1,21 +1,43 @@
 package models
 
 import (
+	"encoding/json"
 	"time"
 
 	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
 	"gorm.io/gorm"
 )
 
+// OrderNumberSequence is the PostgreSQL sequence numbering server-generated order IDs
+const OrderNumberSequence = "order_number_seq"
+
 // Order represents the database model for an order
 type Order struct {
-	ID         string `gorm:"primaryKey"`
-	OrderID    string `gorm:"column:order_id"`
-	CustomerID string `gorm:"column:customer_id"`
-	Status     string
-	CreatedAt  time.Time
-	UpdatedAt  time.Time
-	DeletedAt  gorm.DeletedAt `gorm:"index"`
+	ID            string         `gorm:"primaryKey"`
+	OrderID       string         `gorm:"column:order_id;uniqueIndex:idx_orders_order_id"`
+	CustomerID    string         `gorm:"column:customer_id;index"`
+	Customer      *Customer      `gorm:"foreignKey:CustomerID;constraint:OnDelete:RESTRICT"`
+	CustomerName  string         `gorm:"column:customer_name"`
+	CustomerEmail string         `gorm:"column:customer_email"`
+	Status        string         `gorm:"index"`
+	TotalAmount   int64          `gorm:"not null;default:0"`
+	Currency      string         `gorm:"size:3"`
+	Items         string         `gorm:"type:jsonb;not null;default:'[]'"`
+	Version       int64          `gorm:"not null;default:1"`
+	OrderItems    []OrderItem    `gorm:"foreignKey:OrderID;constraint:OnDelete:CASCADE"`
+	CreatedAt     time.Time      `gorm:"index"`
+	UpdatedAt     time.Time      `gorm:"index"`
+	DeletedAt     gorm.DeletedAt `gorm:"index"`
+}
+
+// OrderSortColumns maps the sortable order fields to their indexed columns
+var OrderSortColumns = map[string]string{
+	entity.OrderSortField.ID:         "id",
+	entity.OrderSortField.OrderID:    "order_id",
+	entity.OrderSortField.CustomerID: "customer_id",
+	entity.OrderSortField.Status:     "status",
+	entity.OrderSortField.CreatedAt:  "created_at",
+	entity.OrderSortField.UpdatedAt:  "updated_at",
 }
 
 // TableName specifies the table name for the Order model
@@ -23,17 +45,66 @@ func (Order) TableName() string {
 	return "orders"
 }
 
+// OrderItem represents the database model for an order line
+type OrderItem struct {
+	ID              string `gorm:"primaryKey"`
+	OrderID         string `gorm:"column:order_id;not null;index"`
+	Position        int    `gorm:"not null"`
+	SKU             string `gorm:"column:sku;not null;index"`
+	Quantity        int    `gorm:"not null"`
+	UnitPriceAmount int64  `gorm:"not null"`
+	LineTotalAmount int64  `gorm:"not null"`
+	Currency        string `gorm:"size:3;not null"`
+	CreatedAt       time.Time
+}
+
+// TableName specifies the table name for the OrderItem model
+func (OrderItem) TableName() string {
+	return "order_items"
+}
+
+// orderItemDocument is the shape of an item in the denormalized items column,
+// which the search index maps as nested objects
+type orderItemDocument struct {
+	ID              string `json:"id"`
+	SKU             string `json:"sku"`
+	Quantity        int    `json:"quantity"`
+	UnitPriceAmount int64  `json:"unit_price_amount"`
+	LineTotalAmount int64  `json:"line_total_amount"`
+	Currency        string `json:"currency"`
+}
+
 // ToEntity converts the model to a domain entity
 func (o *Order) ToEntity() *entity.Order {
-	return &entity.Order{
-		ID:         o.ID,
-		OrderID:    o.OrderID,
-		CustomerID: o.CustomerID,
-		Status:     o.Status,
-		CreatedAt:  o.CreatedAt,
-		UpdatedAt:  o.UpdatedAt,
-		DeletedAt:  o.DeletedAt.Time,
+	items := make([]entity.OrderItem, len(o.OrderItems))
+	for i, item := range o.OrderItems {
+		items[i] = entity.OrderItem{
+			ID:        item.ID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: entity.Money{Amount: item.UnitPriceAmount, Currency: item.Currency},
+			LineTotal: entity.Money{Amount: item.LineTotalAmount, Currency: item.Currency},
+		}
+	}
+
+	order := &entity.Order{
+		ID:            o.ID,
+		OrderID:       o.OrderID,
+		CustomerID:    o.CustomerID,
+		CustomerName:  o.CustomerName,
+		CustomerEmail: o.CustomerEmail,
+		Status:        o.Status,
+		Items:         items,
+		Total:         entity.Money{Amount: o.TotalAmount, Currency: o.Currency},
+		Version:       o.Version,
+		CreatedAt:     o.CreatedAt,
+		UpdatedAt:     o.UpdatedAt,
 	}
+	if o.DeletedAt.Valid {
+		deletedAt := o.DeletedAt.Time
+		order.DeletedAt = &deletedAt
+	}
+	return order
 }
 
 // FromEntity converts a domain entity to a model
@@ -41,7 +112,38 @@ func (o *Order) FromEntity(order *entity.Order) {
 	o.ID = order.ID
 	o.OrderID = order.OrderID
 	o.CustomerID = order.CustomerID
+	o.CustomerName = order.CustomerName
+	o.CustomerEmail = order.CustomerEmail
 	o.Status = order.Status
+	o.TotalAmount = order.Total.Amount
+	o.Currency = order.Total.Currency
+	o.Version = order.Version
 	o.CreatedAt = order.CreatedAt
 	o.UpdatedAt = order.UpdatedAt
+
+	o.OrderItems = make([]OrderItem, len(order.Items))
+	documents := make([]orderItemDocument, len(order.Items))
+	for i, item := range order.Items {
+		o.OrderItems[i] = OrderItem{
+			ID:              item.ID,
+			OrderID:         order.ID,
+			Position:        i,
+			SKU:             item.SKU,
+			Quantity:        item.Quantity,
+			UnitPriceAmount: item.UnitPrice.Amount,
+			LineTotalAmount: item.LineTotal.Amount,
+			Currency:        item.UnitPrice.Currency,
+			CreatedAt:       order.UpdatedAt,
+		}
+		documents[i] = orderItemDocument{
+			ID:              item.ID,
+			SKU:             item.SKU,
+			Quantity:        item.Quantity,
+			UnitPriceAmount: item.UnitPrice.Amount,
+			LineTotalAmount: item.LineTotal.Amount,
+			Currency:        item.UnitPrice.Currency,
+		}
+	}
+	items, _ := json.Marshal(documents)
+	o.Items = string(items)
 }
@@ -0,0 +1,42 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// OrderFlag represents the database model for an order flagged by an automation rule.
+// An order is flagged at most once per rule.
+type OrderFlag struct {
+	ID        string    `gorm:"primaryKey"`
+	OrderID   string    `gorm:"column:order_id;not null;uniqueIndex:idx_order_flags_order_rule"`
+	Rule      string    `gorm:"not null;uniqueIndex:idx_order_flags_order_rule"`
+	Reason    string    `gorm:"type:text;not null;default:''"`
+	FlaggedAt time.Time `gorm:"not null"`
+}
+
+// TableName specifies the table name for the OrderFlag model
+func (OrderFlag) TableName() string {
+	return "order_flags"
+}
+
+// ToEntity converts the model to a domain entity
+func (f *OrderFlag) ToEntity() *entity.OrderFlag {
+	return &entity.OrderFlag{
+		ID:        f.ID,
+		OrderID:   f.OrderID,
+		Rule:      f.Rule,
+		Reason:    f.Reason,
+		FlaggedAt: f.FlaggedAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (f *OrderFlag) FromEntity(flag *entity.OrderFlag) {
+	f.ID = flag.ID
+	f.OrderID = flag.OrderID
+	f.Rule = flag.Rule
+	f.Reason = flag.Reason
+	f.FlaggedAt = flag.FlaggedAt
+}
@@ -0,0 +1,48 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// OrderStatusHistory represents the database model for an order status change.
+// Rows are kept when their order is deleted, so there is no foreign key.
+type OrderStatusHistory struct {
+	ID         string    `gorm:"primaryKey"`
+	OrderID    string    `gorm:"column:order_id;not null;index"`
+	FromStatus string    `gorm:"not null;default:''"`
+	ToStatus   string    `gorm:"not null"`
+	Actor      string    `gorm:"not null;default:''"`
+	Reason     string    `gorm:"type:text;not null;default:''"`
+	ChangedAt  time.Time `gorm:"not null;index"`
+}
+
+// TableName specifies the table name for the OrderStatusHistory model
+func (OrderStatusHistory) TableName() string {
+	return "order_status_history"
+}
+
+// ToEntity converts the model to a domain entity
+func (h *OrderStatusHistory) ToEntity() *entity.OrderStatusChange {
+	return &entity.OrderStatusChange{
+		ID:         h.ID,
+		OrderID:    h.OrderID,
+		FromStatus: h.FromStatus,
+		ToStatus:   h.ToStatus,
+		Actor:      h.Actor,
+		Reason:     h.Reason,
+		ChangedAt:  h.ChangedAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (h *OrderStatusHistory) FromEntity(change *entity.OrderStatusChange) {
+	h.ID = change.ID
+	h.OrderID = change.OrderID
+	h.FromStatus = change.FromStatus
+	h.ToStatus = change.ToStatus
+	h.Actor = change.Actor
+	h.Reason = change.Reason
+	h.ChangedAt = change.ChangedAt
+}
@@ -0,0 +1,182 @@
+package models
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// orderEventSchemaVersion is the version of the order event payload schema. It changes
+// only when a field is removed or changes meaning; new fields keep the version.
+const orderEventSchemaVersion = 1
+
+// OrderAggregateType routes order events to their topic in the outbox event router
+const OrderAggregateType = "order"
+
+// OutboxEvent represents the database model for an event in the outbox. The column names
+// are the defaults of the Debezium outbox event router. TxID and Sequence are set by the
+// database and give the event its position in the outbox; their columns are added by the
+// migrations rather than AutoMigrate.
+type OutboxEvent struct {
+	ID            string    `gorm:"primaryKey"`
+	AggregateType string    `gorm:"column:aggregatetype;not null"`
+	AggregateID   string    `gorm:"column:aggregateid;not null;index"`
+	Type          string    `gorm:"column:type;not null"`
+	Payload       string    `gorm:"type:jsonb;not null"`
+	OccurredAt    time.Time `gorm:"not null;index"`
+	TxID          int64     `gorm:"->;-:migration;column:txid"`
+	Sequence      int64     `gorm:"->;-:migration;column:sequence"`
+}
+
+// TableName specifies the table name for the OutboxEvent model
+func (OutboxEvent) TableName() string {
+	return "outbox"
+}
+
+// orderEventPayload is the published JSON of an order event. It is decoupled from the
+// API representation of orders so the event schema only changes deliberately.
+type orderEventPayload struct {
+	SchemaVersion int               `json:"schemaVersion"`
+	EventID       string            `json:"eventId"`
+	EventType     string            `json:"eventType"`
+	OccurredAt    time.Time         `json:"occurredAt"`
+	Actor         string            `json:"actor"`
+	OrderID       string            `json:"orderId"`
+	FromStatus    string            `json:"fromStatus,omitempty"`
+	ToStatus      string            `json:"toStatus,omitempty"`
+	Reason        string            `json:"reason,omitempty"`
+	Order         orderEventDetails `json:"order"`
+}
+
+// orderEventDetails is the state of the order after the event
+type orderEventDetails struct {
+	ID            string           `json:"id"`
+	OrderID       string           `json:"orderId"`
+	CustomerID    string           `json:"customerId"`
+	CustomerName  string           `json:"customerName"`
+	CustomerEmail string           `json:"customerEmail"`
+	Status        string           `json:"status"`
+	Items         []orderEventItem `json:"items"`
+	Total         orderEventMoney  `json:"total"`
+	Version       int64            `json:"version"`
+	CreatedAt     time.Time        `json:"createdAt"`
+	UpdatedAt     time.Time        `json:"updatedAt"`
+	DeletedAt     *time.Time       `json:"deletedAt,omitempty"`
+}
+
+// orderEventItem is an order line in an order event
+type orderEventItem struct {
+	ID        string          `json:"id"`
+	SKU       string          `json:"sku"`
+	Quantity  int             `json:"quantity"`
+	UnitPrice orderEventMoney `json:"unitPrice"`
+	LineTotal orderEventMoney `json:"lineTotal"`
+}
+
+// orderEventMoney is an amount in minor units of a currency
+type orderEventMoney struct {
+	Amount   int64  `json:"amount"`
+	Currency string `json:"currency"`
+}
+
+// FromOrderEvent converts a domain event to an outbox model
+func (e *OutboxEvent) FromOrderEvent(event *entity.OrderEvent) error {
+	order := &event.Order
+	details := orderEventDetails{
+		ID:            order.ID,
+		OrderID:       order.OrderID,
+		CustomerID:    order.CustomerID,
+		CustomerName:  order.CustomerName,
+		CustomerEmail: order.CustomerEmail,
+		Status:        order.Status,
+		Items:         make([]orderEventItem, len(order.Items)),
+		Total:         orderEventMoney{Amount: order.Total.Amount, Currency: order.Total.Currency},
+		Version:       order.Version,
+		CreatedAt:     order.CreatedAt,
+		UpdatedAt:     order.UpdatedAt,
+		DeletedAt:     order.DeletedAt,
+	}
+	for i, item := range order.Items {
+		details.Items[i] = orderEventItem{
+			ID:        item.ID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: orderEventMoney{Amount: item.UnitPrice.Amount, Currency: item.UnitPrice.Currency},
+			LineTotal: orderEventMoney{Amount: item.LineTotal.Amount, Currency: item.LineTotal.Currency},
+		}
+	}
+
+	payload, err := json.Marshal(orderEventPayload{
+		SchemaVersion: orderEventSchemaVersion,
+		EventID:       event.ID,
+		EventType:     event.Type,
+		OccurredAt:    event.OccurredAt,
+		Actor:         event.Actor,
+		OrderID:       event.OrderID,
+		FromStatus:    event.FromStatus,
+		ToStatus:      event.ToStatus,
+		Reason:        event.Reason,
+		Order:         details,
+	})
+	if err != nil {
+		return err
+	}
+
+	e.ID = event.ID
+	e.AggregateType = OrderAggregateType
+	e.AggregateID = event.OrderID
+	e.Type = event.Type
+	e.Payload = string(payload)
+	e.OccurredAt = event.OccurredAt
+	return nil
+}
+
+// ToOrderEntry converts an outbox model back to the domain event at its position
+func (e *OutboxEvent) ToOrderEntry() (*entity.OutboxEntry, error) {
+	var payload orderEventPayload
+	if err := json.Unmarshal([]byte(e.Payload), &payload); err != nil {
+		return nil, fmt.Errorf("failed to decode outbox event %s: %w", e.ID, err)
+	}
+
+	details := &payload.Order
+	order := entity.Order{
+		ID:            details.ID,
+		OrderID:       details.OrderID,
+		CustomerID:    details.CustomerID,
+		CustomerName:  details.CustomerName,
+		CustomerEmail: details.CustomerEmail,
+		Status:        details.Status,
+		Items:         make([]entity.OrderItem, len(details.Items)),
+		Total:         entity.Money{Amount: details.Total.Amount, Currency: details.Total.Currency},
+		Version:       details.Version,
+		CreatedAt:     details.CreatedAt,
+		UpdatedAt:     details.UpdatedAt,
+		DeletedAt:     details.DeletedAt,
+	}
+	for i, item := range details.Items {
+		order.Items[i] = entity.OrderItem{
+			ID:        item.ID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: entity.Money{Amount: item.UnitPrice.Amount, Currency: item.UnitPrice.Currency},
+			LineTotal: entity.Money{Amount: item.LineTotal.Amount, Currency: item.LineTotal.Currency},
+		}
+	}
+
+	return &entity.OutboxEntry{
+		Position: entity.OutboxPosition{TxID: e.TxID, Sequence: e.Sequence},
+		Event: entity.OrderEvent{
+			ID:         payload.EventID,
+			Type:       payload.EventType,
+			OrderID:    payload.OrderID,
+			Actor:      payload.Actor,
+			OccurredAt: payload.OccurredAt,
+			Order:      order,
+			FromStatus: payload.FromStatus,
+			ToStatus:   payload.ToStatus,
+			Reason:     payload.Reason,
+		},
+	}, nil
+}
@@ -0,0 +1,65 @@
+package models
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+func TestOutboxEventRoundTrip(t *testing.T) {
+	createdAt := time.Date(2026, 10, 19, 9, 0, 0, 0, time.UTC)
+	deletedAt := createdAt.Add(time.Hour)
+	tests := []struct {
+		name  string
+		event entity.OrderEvent
+	}{
+		{"status change", entity.OrderEvent{
+			ID: "event-1", Type: entity.OrderEventType.StatusChanged, OrderID: "order-1", Actor: "customer:511",
+			OccurredAt: createdAt.Add(time.Minute), FromStatus: entity.OrderStatus.New, ToStatus: entity.OrderStatus.Processing,
+			Reason: "paid",
+			Order: entity.Order{
+				ID: "order-1", OrderID: "1001", CustomerID: "511", CustomerName: "Ada", CustomerEmail: "ada@example.com",
+				Status: entity.OrderStatus.Processing, Version: 4, CreatedAt: createdAt, UpdatedAt: createdAt.Add(time.Minute),
+				Items: []entity.OrderItem{{ID: "item-1", SKU: "SKU-1", Quantity: 2,
+					UnitPrice: entity.Money{Amount: 1250, Currency: "EUR"}, LineTotal: entity.Money{Amount: 2500, Currency: "EUR"}}},
+				Total: entity.Money{Amount: 2500, Currency: "EUR"},
+			},
+		}},
+		{"purge without items", entity.OrderEvent{
+			ID: "event-2", Type: entity.OrderEventType.Purged, OrderID: "order-2", Actor: "system",
+			OccurredAt: deletedAt,
+			Order: entity.Order{ID: "order-2", OrderID: "1002", CustomerID: "512", Status: entity.OrderStatus.Cancelled,
+				Items: []entity.OrderItem{}, Version: 7, CreatedAt: createdAt, UpdatedAt: deletedAt, DeletedAt: &deletedAt},
+		}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var model OutboxEvent
+			if err := model.FromOrderEvent(&tt.event); err != nil {
+				t.Fatalf("FromOrderEvent() error = %v", err)
+			}
+			model.TxID, model.Sequence = 812, 3
+
+			entry, err := model.ToOrderEntry()
+			if err != nil {
+				t.Fatalf("ToOrderEntry() error = %v", err)
+			}
+			if want := (entity.OutboxPosition{TxID: 812, Sequence: 3}); entry.Position != want {
+				t.Errorf("position = %v, want %v", entry.Position, want)
+			}
+			if !reflect.DeepEqual(entry.Event, tt.event) {
+				t.Errorf("ToOrderEntry() event = %+v, want %+v", entry.Event, tt.event)
+			}
+		})
+	}
+}
+
+func TestToOrderEntryRejectsInvalidPayload(t *testing.T) {
+	model := OutboxEvent{ID: "event-1", Payload: `{"order":`}
+	if _, err := model.ToOrderEntry(); err == nil {
+		t.Error("ToOrderEntry() of a truncated payload succeeded")
+	}
+}
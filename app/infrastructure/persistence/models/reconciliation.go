@@ -0,0 +1,71 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// ReconciliationReport represents the database model for a reconciliation report
+type ReconciliationReport struct {
+	ID          string `gorm:"primaryKey"`
+	Status      string
+	Repair      bool
+	Scanned     int
+	Matching    int
+	Missing     int
+	Stale       int
+	Orphaned    int
+	Repaired    int
+	MissingIDs  []string `gorm:"column:missing_ids;serializer:json"`
+	StaleIDs    []string `gorm:"column:stale_ids;serializer:json"`
+	OrphanedIDs []string `gorm:"column:orphaned_ids;serializer:json"`
+	Error       string
+	StartedAt   time.Time `gorm:"index"`
+	FinishedAt  *time.Time
+}
+
+// TableName specifies the table name for the ReconciliationReport model
+func (ReconciliationReport) TableName() string {
+	return "reconciliation_reports"
+}
+
+// ToEntity converts the model to a domain entity
+func (r *ReconciliationReport) ToEntity() *entity.ReconciliationReport {
+	return &entity.ReconciliationReport{
+		ID:          r.ID,
+		Status:      r.Status,
+		Repair:      r.Repair,
+		Scanned:     r.Scanned,
+		Matching:    r.Matching,
+		Missing:     r.Missing,
+		Stale:       r.Stale,
+		Orphaned:    r.Orphaned,
+		Repaired:    r.Repaired,
+		MissingIDs:  r.MissingIDs,
+		StaleIDs:    r.StaleIDs,
+		OrphanedIDs: r.OrphanedIDs,
+		Error:       r.Error,
+		StartedAt:   r.StartedAt,
+		FinishedAt:  r.FinishedAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (r *ReconciliationReport) FromEntity(report *entity.ReconciliationReport) {
+	r.ID = report.ID
+	r.Status = report.Status
+	r.Repair = report.Repair
+	r.Scanned = report.Scanned
+	r.Matching = report.Matching
+	r.Missing = report.Missing
+	r.Stale = report.Stale
+	r.Orphaned = report.Orphaned
+	r.Repaired = report.Repaired
+	r.MissingIDs = report.MissingIDs
+	r.StaleIDs = report.StaleIDs
+	r.OrphanedIDs = report.OrphanedIDs
+	r.Error = report.Error
+	r.StartedAt = report.StartedAt
+	r.FinishedAt = report.FinishedAt
+}
@@ -0,0 +1,93 @@
+package models
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// DebeziumSignal represents the Debezium signalling table.
+// Debezium inserts snapshot watermarks positionally into the first three
+// columns, so their order must not change; CreatedAt is filled by its default,
+// also for the signals written here when it is zero, so all rows share the database clock.
+type DebeziumSignal struct {
+	ID        string    `gorm:"primaryKey;size:42"`
+	Type      string    `gorm:"size:32;not null"`
+	Data      string    `gorm:"type:text"`
+	CreatedAt time.Time `gorm:"not null;default:now();index"`
+}
+
+// TableName specifies the table name for the DebeziumSignal model
+func (DebeziumSignal) TableName() string {
+	return "debezium_signal"
+}
+
+// ToEntity converts the model to a domain entity
+func (s *DebeziumSignal) ToEntity() *entity.Signal {
+	return &entity.Signal{
+		ID:        s.ID,
+		Type:      s.Type,
+		Data:      s.Data,
+		CreatedAt: s.CreatedAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (s *DebeziumSignal) FromEntity(signal *entity.Signal) {
+	s.ID = signal.ID
+	s.Type = signal.Type
+	s.Data = signal.Data
+	s.CreatedAt = signal.CreatedAt
+}
+
+// SnapshotRequest represents the database model for a snapshot request
+type SnapshotRequest struct {
+	ID              string                     `gorm:"primaryKey"`
+	DataCollections []string                   `gorm:"serializer:json"`
+	Conditions      []entity.SnapshotCondition `gorm:"serializer:json"`
+	Status          string                     `gorm:"index"`
+	ChunksCompleted int
+	SignalledAt     *time.Time
+	StartedAt       *time.Time
+	LastChunkAt     *time.Time
+	CompletedAt     *time.Time
+	CreatedAt       time.Time
+	UpdatedAt       time.Time
+}
+
+// TableName specifies the table name for the SnapshotRequest model
+func (SnapshotRequest) TableName() string {
+	return "snapshot_requests"
+}
+
+// ToEntity converts the model to a domain entity
+func (r *SnapshotRequest) ToEntity() *entity.SnapshotRequest {
+	return &entity.SnapshotRequest{
+		ID:              r.ID,
+		DataCollections: r.DataCollections,
+		Conditions:      r.Conditions,
+		Status:          r.Status,
+		ChunksCompleted: r.ChunksCompleted,
+		SignalledAt:     r.SignalledAt,
+		StartedAt:       r.StartedAt,
+		LastChunkAt:     r.LastChunkAt,
+		CompletedAt:     r.CompletedAt,
+		CreatedAt:       r.CreatedAt,
+		UpdatedAt:       r.UpdatedAt,
+	}
+}
+
+// FromEntity converts a domain entity to a model
+func (r *SnapshotRequest) FromEntity(request *entity.SnapshotRequest) {
+	r.ID = request.ID
+	r.DataCollections = request.DataCollections
+	r.Conditions = request.Conditions
+	r.Status = request.Status
+	r.ChunksCompleted = request.ChunksCompleted
+	r.SignalledAt = request.SignalledAt
+	r.StartedAt = request.StartedAt
+	r.LastChunkAt = request.LastChunkAt
+	r.CompletedAt = request.CompletedAt
+	r.CreatedAt = request.CreatedAt
+	r.UpdatedAt = request.UpdatedAt
+}
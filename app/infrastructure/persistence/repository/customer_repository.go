@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormCustomerRepository implements the CustomerRepository interface using GORM
+type GormCustomerRepository struct {
+	db *gorm.DB
+}
+
+// NewGormCustomerRepository creates a new GormCustomerRepository
+func NewGormCustomerRepository(db *gorm.DB) repository.CustomerRepository {
+	return &GormCustomerRepository{
+		db: db,
+	}
+}
+
+// FindAll retrieves all customers
+func (r *GormCustomerRepository) FindAll(ctx context.Context) ([]entity.Customer, error) {
+	var customerModels []models.Customer
+	if err := dbFromContext(ctx, r.db).Order("name").Find(&customerModels).Error; err != nil {
+		return nil, err
+	}
+
+	customers := make([]entity.Customer, len(customerModels))
+	for i, model := range customerModels {
+		customers[i] = *model.ToEntity()
+	}
+
+	return customers, nil
+}
+
+// FindByID retrieves a customer by its ID
+func (r *GormCustomerRepository) FindByID(ctx context.Context, id string) (*entity.Customer, error) {
+	var customerModel models.Customer
+	if err := dbFromContext(ctx, r.db).First(&customerModel, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil, nil when not found
+		}
+		return nil, err
+	}
+
+	return customerModel.ToEntity(), nil
+}
+
+// FindByIDForShare retrieves a customer by its ID, locking its row FOR SHARE until the
+// transaction carried by ctx ends
+func (r *GormCustomerRepository) FindByIDForShare(ctx context.Context, id string) (*entity.Customer, error) {
+	var customerModel models.Customer
+	if err := dbFromContext(ctx, r.db).Clauses(clause.Locking{Strength: "SHARE"}).
+		First(&customerModel, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return customerModel.ToEntity(), nil
+}
+
+// FindByIDs retrieves the customers with the given IDs keyed by ID
+func (r *GormCustomerRepository) FindByIDs(ctx context.Context, ids []string) (map[string]entity.Customer, error) {
+	var customerModels []models.Customer
+	if err := dbFromContext(ctx, r.db).Where("id IN ?", ids).Find(&customerModels).Error; err != nil {
+		return nil, err
+	}
+
+	customers := make(map[string]entity.Customer, len(customerModels))
+	for _, model := range customerModels {
+		customers[model.ID] = *model.ToEntity()
+	}
+
+	return customers, nil
+}
+
+// HasOrders reports whether any order, including soft-deleted ones, references the customer
+func (r *GormCustomerRepository) HasOrders(ctx context.Context, id string) (bool, error) {
+	var count int64
+	if err := dbFromContext(ctx, r.db).Unscoped().
+		Model(&models.Order{}).
+		Where("customer_id = ?", id).
+		Limit(1).
+		Count(&count).Error; err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+// Create creates a new customer
+func (r *GormCustomerRepository) Create(ctx context.Context, customer *entity.Customer) error {
+	customerModel := models.Customer{}
+	customerModel.FromEntity(customer)
+
+	if err := dbFromContext(ctx, r.db).Create(&customerModel).Error; err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return fmt.Errorf("%w: id %s is already used", entity.ErrDuplicateCustomer, customer.ID)
+		}
+		return err
+	}
+	return nil
+}
+
+// Update updates an existing customer
+func (r *GormCustomerRepository) Update(ctx context.Context, customer *entity.Customer) error {
+	customerModel := models.Customer{}
+	customerModel.FromEntity(customer)
+
+	return dbFromContext(ctx, r.db).Save(&customerModel).Error
+}
+
+// Delete deletes a customer by its ID
+func (r *GormCustomerRepository) Delete(ctx context.Context, id string) error {
+	return dbFromContext(ctx, r.db).Delete(&models.Customer{}, "id = ?", id).Error
+}
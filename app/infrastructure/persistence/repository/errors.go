@@ -0,0 +1,64 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"database/sql/driver"
+	"errors"
+	"fmt"
+	"net"
+	"strings"
+
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"gorm.io/gorm"
+)
+
+// unavailableCallback names the GORM callbacks that mark connection failures as ErrUnavailable
+const unavailableCallback = "app:unavailable"
+
+// RegisterErrorTranslation makes every query of db that fails because PostgreSQL cannot be
+// reached return an error of kind ErrUnavailable, so callers can tell outages from bugs
+func RegisterErrorTranslation(db *gorm.DB) error {
+	callbacks := db.Callback()
+	for _, register := range []func(string, func(*gorm.DB)) error{
+		callbacks.Create().After("*").Register,
+		callbacks.Query().After("*").Register,
+		callbacks.Update().After("*").Register,
+		callbacks.Delete().After("*").Register,
+		callbacks.Row().After("*").Register,
+		callbacks.Raw().After("*").Register,
+	} {
+		if err := register(unavailableCallback, markUnavailable); err != nil {
+			return fmt.Errorf("failed to register error translation: %w", err)
+		}
+	}
+	return nil
+}
+
+// markUnavailable wraps the error of a statement that failed to reach PostgreSQL
+func markUnavailable(db *gorm.DB) {
+	if db.Error != nil && isUnavailable(db.Error) && !errors.Is(db.Error, entity.ErrUnavailable) {
+		db.Error = fmt.Errorf("%w: %w", entity.ErrUnavailable, db.Error)
+	}
+}
+
+// isUnavailable reports whether err means PostgreSQL could not be reached or refused to serve
+func isUnavailable(err error) bool {
+	var connectErr *pgconn.ConnectError
+	var netErr net.Error
+	if errors.Is(err, driver.ErrBadConn) ||
+		errors.Is(err, sql.ErrConnDone) ||
+		errors.Is(err, context.DeadlineExceeded) ||
+		errors.As(err, &connectErr) ||
+		errors.As(err, &netErr) {
+		return true
+	}
+
+	// Connection exceptions, too many connections and server shutdowns
+	var pgErr *pgconn.PgError
+	if errors.As(err, &pgErr) {
+		return strings.HasPrefix(pgErr.Code, "08") || pgErr.Code == "53300" || strings.HasPrefix(pgErr.Code, "57P")
+	}
+	return false
+}
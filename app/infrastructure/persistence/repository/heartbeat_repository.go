@@ -0,0 +1,31 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormHeartbeatRepository implements the HeartbeatRepository interface using GORM
+type GormHeartbeatRepository struct {
+	db *gorm.DB
+}
+
+// NewGormHeartbeatRepository creates a new GormHeartbeatRepository
+func NewGormHeartbeatRepository(db *gorm.DB) repository.HeartbeatRepository {
+	return &GormHeartbeatRepository{
+		db: db,
+	}
+}
+
+// Upsert creates or replaces the heartbeat row
+func (r *GormHeartbeatRepository) Upsert(ctx context.Context, heartbeat *entity.Heartbeat) error {
+	heartbeatModel := models.Heartbeat{}
+	heartbeatModel.FromEntity(heartbeat)
+
+	return r.db.WithContext(ctx).Clauses(clause.OnConflict{UpdateAll: true}).Create(&heartbeatModel).Error
+}
@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// GormIdempotencyRepository implements the IdempotencyRepository interface using GORM
+type GormIdempotencyRepository struct {
+	db *gorm.DB
+}
+
+// NewGormIdempotencyRepository creates a new GormIdempotencyRepository
+func NewGormIdempotencyRepository(db *gorm.DB) repository.IdempotencyRepository {
+	return &GormIdempotencyRepository{
+		db: db,
+	}
+}
+
+// Reserve stores record unless its key is held by an unexpired record, which is returned
+// instead. The same request takes over a key whose lease ran out, as when the replica
+// handling it crashed; a concurrent retry sees the renewed lease and backs off.
+func (r *GormIdempotencyRepository) Reserve(ctx context.Context, record *entity.IdempotencyRecord) (*entity.IdempotencyRecord, error) {
+	model := models.IdempotencyKey{}
+	model.FromEntity(record)
+
+	var existing *entity.IdempotencyRecord
+	err := r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// An expired record frees its key even before the pruning job removes it
+		if err := tx.Where("scope = ? AND key = ? AND expires_at <= ?", record.Scope, record.Key, record.CreatedAt).
+			Delete(&models.IdempotencyKey{}).Error; err != nil {
+			return err
+		}
+
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&model)
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+
+		result = tx.Model(&models.IdempotencyKey{}).
+			Where("scope = ? AND key = ? AND request_hash = ?", record.Scope, record.Key, record.RequestHash).
+			Where("NOT completed AND lease_expires_at <= ?", record.CreatedAt).
+			Updates(map[string]any{
+				"created_at":       record.CreatedAt,
+				"lease_expires_at": record.LeaseExpiresAt,
+				"expires_at":       record.ExpiresAt,
+			})
+		if result.Error != nil || result.RowsAffected > 0 {
+			return result.Error
+		}
+
+		var held models.IdempotencyKey
+		if err := tx.First(&held, "scope = ? AND key = ?", record.Scope, record.Key).Error; err != nil {
+			return err
+		}
+		existing = held.ToEntity()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// Complete stores the response of a reserved record
+func (r *GormIdempotencyRepository) Complete(ctx context.Context, record *entity.IdempotencyRecord) error {
+	return r.db.WithContext(ctx).Model(&models.IdempotencyKey{}).
+		Where("scope = ? AND key = ?", record.Scope, record.Key).
+		Updates(map[string]any{
+			"completed":    true,
+			"status_code":  record.StatusCode,
+			"content_type": record.ContentType,
+			"etag":         record.ETag,
+			"body":         record.Body,
+		}).Error
+}
+
+// Release deletes a reserved record that did not complete
+func (r *GormIdempotencyRepository) Release(ctx context.Context, scope, key string) error {
+	return r.db.WithContext(ctx).
+		Where("scope = ? AND key = ? AND NOT completed", scope, key).
+		Delete(&models.IdempotencyKey{}).Error
+}
+
+// DeleteExpired deletes records that expired before the given time and returns how many were deleted
+func (r *GormIdempotencyRepository) DeleteExpired(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("expires_at < ?", before).Delete(&models.IdempotencyKey{})
+	return result.RowsAffected, result.Error
+}
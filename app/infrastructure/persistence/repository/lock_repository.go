@@ -0,0 +1,62 @@
+package repository
+
+import (
+	"context"
+	"hash/fnv"
+	"log"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"gorm.io/gorm"
+)
+
+// PostgresLockRepository implements the LockRepository interface using PostgreSQL advisory locks
+type PostgresLockRepository struct {
+	db *gorm.DB
+}
+
+// NewPostgresLockRepository creates a new PostgresLockRepository
+func NewPostgresLockRepository(db *gorm.DB) repository.LockRepository {
+	return &PostgresLockRepository{
+		db: db,
+	}
+}
+
+// TryLock takes a session-level advisory lock keyed by a hash of name. The lock lives on a
+// dedicated connection, which is returned to the pool on release; a crashed replica's lock
+// is freed when its connection closes.
+func (r *PostgresLockRepository) TryLock(ctx context.Context, name string) (func(), bool, error) {
+	sqlDB, err := r.db.DB()
+	if err != nil {
+		return nil, false, err
+	}
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return nil, false, err
+	}
+
+	key := advisoryLockKey(name)
+	var acquired bool
+	if err := conn.QueryRowContext(ctx, "SELECT pg_try_advisory_lock($1)", key).Scan(&acquired); err != nil {
+		conn.Close()
+		return nil, false, err
+	}
+	if !acquired {
+		conn.Close()
+		return nil, false, nil
+	}
+
+	release := func() {
+		if _, err := conn.ExecContext(context.Background(), "SELECT pg_advisory_unlock($1)", key); err != nil {
+			log.Printf("Failed to release lock %s: %v", name, err)
+		}
+		conn.Close()
+	}
+	return release, true, nil
+}
+
+// advisoryLockKey hashes a lock name to an advisory lock key
+func advisoryLockKey(name string) int64 {
+	hash := fnv.New64a()
+	hash.Write([]byte(name))
+	return int64(hash.Sum64())
+}
@@ -3,11 +3,14 @@ package repository
 import (
 	"context"
 	"errors"
+	"fmt"
+	"time"
 
 	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
 	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
 	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 // GormOrderRepository implements the OrderRepository interface using GORM
@@ -22,10 +25,15 @@ func NewGormOrderRepository(db *gorm.DB) repository.OrderRepository {
 	}
 }
 
+// orderItemsByPosition preloads order items in the order they were given
+func orderItemsByPosition(db *gorm.DB) *gorm.DB {
+	return db.Order("position")
+}
+
 // FindAll retrieves all orders
 func (r *GormOrderRepository) FindAll(ctx context.Context) ([]entity.Order, error) {
 	var orderModels []models.Order
-	if err := r.db.WithContext(ctx).Find(&orderModels).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Preload("OrderItems", orderItemsByPosition).Find(&orderModels).Error; err != nil {
 		return nil, err
 	}
 
@@ -37,10 +45,60 @@ func (r *GormOrderRepository) FindAll(ctx context.Context) ([]entity.Order, erro
 	return orders, nil
 }
 
+// Find retrieves the page of orders selected by query and the number of orders matching it
+func (r *GormOrderRepository) Find(ctx context.Context, query entity.OrderQuery) ([]entity.Order, int64, error) {
+	column, ok := models.OrderSortColumns[query.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: cannot sort by %q", entity.ErrInvalidOrderQuery, query.Sort)
+	}
+
+	db := dbFromContext(ctx, r.db).Model(&models.Order{})
+	if len(query.Statuses) > 0 {
+		db = db.Where("status IN ?", query.Statuses)
+	}
+	if query.CustomerID != "" {
+		db = db.Where("customer_id = ?", query.CustomerID)
+	}
+	if query.CreatedFrom != nil {
+		db = db.Where("created_at >= ?", *query.CreatedFrom)
+	}
+	if query.CreatedTo != nil {
+		db = db.Where("created_at < ?", *query.CreatedTo)
+	}
+	if query.UpdatedFrom != nil {
+		db = db.Where("updated_at >= ?", *query.UpdatedFrom)
+	}
+	if query.UpdatedTo != nil {
+		db = db.Where("updated_at < ?", *query.UpdatedTo)
+	}
+
+	var total int64
+	if err := db.Session(&gorm.Session{}).Count(&total).Error; err != nil {
+		return nil, 0, err
+	}
+
+	var orderModels []models.Order
+	if err := db.Preload("OrderItems", orderItemsByPosition).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: query.Descending}).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: "id"}, Desc: query.Descending}).
+		Limit(query.Limit).
+		Offset(query.Offset).
+		Find(&orderModels).Error; err != nil {
+		return nil, 0, err
+	}
+
+	orders := make([]entity.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = *model.ToEntity()
+	}
+
+	return orders, total, nil
+}
+
 // FindByID retrieves an order by its ID
 func (r *GormOrderRepository) FindByID(ctx context.Context, id string) (*entity.Order, error) {
 	var orderModel models.Order
-	if err := r.db.WithContext(ctx).First(&orderModel, "id = ?", id).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Preload("OrderItems", orderItemsByPosition).First(&orderModel, "id = ?", id).Error; err != nil {
 		if errors.Is(err, gorm.ErrRecordNotFound) {
 			return nil, nil // Return nil, nil when not found
 		}
@@ -53,7 +111,50 @@ func (r *GormOrderRepository) FindByID(ctx context.Context, id string) (*entity.
 // FindByStatus retrieves orders by status
 func (r *GormOrderRepository) FindByStatus(ctx context.Context, status string) ([]entity.Order, error) {
 	var orderModels []models.Order
-	if err := r.db.WithContext(ctx).Where("status = ?", status).Find(&orderModels).Error; err != nil {
+	if err := dbFromContext(ctx, r.db).Preload("OrderItems", orderItemsByPosition).Where("status = ?", status).Find(&orderModels).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]entity.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = *model.ToEntity()
+	}
+
+	return orders, nil
+}
+
+// FindAfterID retrieves up to limit orders, including soft-deleted ones, with IDs greater than afterID ordered by ID
+func (r *GormOrderRepository) FindAfterID(ctx context.Context, afterID string, limit int) ([]entity.Order, error) {
+	var orderModels []models.Order
+	if err := dbFromContext(ctx, r.db).Unscoped().
+		Preload("OrderItems", orderItemsByPosition).
+		Where("id > ?", afterID).
+		Order("id").
+		Limit(limit).
+		Find(&orderModels).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]entity.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = *model.ToEntity()
+	}
+
+	return orders, nil
+}
+
+// FindWithStaleCustomer retrieves the orders of a customer, including soft-deleted ones, whose
+// copied name or email differ from the customer's. The rows are locked until the transaction
+// carried by ctx ends, so concurrent order updates wait for the customer update.
+func (r *GormOrderRepository) FindWithStaleCustomer(ctx context.Context, customer *entity.Customer) ([]entity.Order, error) {
+	var orderModels []models.Order
+	if err := dbFromContext(ctx, r.db).Unscoped().
+		Clauses(clause.Locking{Strength: "UPDATE"}).
+		Preload("OrderItems", orderItemsByPosition).
+		Where("customer_id = ?", customer.ID).
+		Where("customer_name IS DISTINCT FROM ? OR customer_email IS DISTINCT FROM ?", customer.Name, customer.Email).
+		Order("id").
+		Find(&orderModels).Error; err != nil {
 		return nil, err
 	}
 
@@ -65,23 +166,388 @@ func (r *GormOrderRepository) FindByStatus(ctx context.Context, status string) (
 	return orders, nil
 }
 
-// Create creates a new order
-func (r *GormOrderRepository) Create(ctx context.Context, order *entity.Order) error {
+// FindExistingIDs returns the subset of ids that exist, including soft-deleted orders
+func (r *GormOrderRepository) FindExistingIDs(ctx context.Context, ids []string) ([]string, error) {
+	var existing []string
+	if err := dbFromContext(ctx, r.db).Unscoped().
+		Model(&models.Order{}).
+		Where("id IN ?", ids).
+		Pluck("id", &existing).Error; err != nil {
+		return nil, err
+	}
+
+	return existing, nil
+}
+
+// FindDeleted retrieves all soft-deleted orders, most recently deleted first
+func (r *GormOrderRepository) FindDeleted(ctx context.Context) ([]entity.Order, error) {
+	var orderModels []models.Order
+	if err := dbFromContext(ctx, r.db).Unscoped().
+		Preload("OrderItems", orderItemsByPosition).
+		Where("deleted_at IS NOT NULL").
+		Order("deleted_at DESC").
+		Find(&orderModels).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]entity.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = *model.ToEntity()
+	}
+
+	return orders, nil
+}
+
+// FindDeletedByID retrieves a soft-deleted order by its ID
+func (r *GormOrderRepository) FindDeletedByID(ctx context.Context, id string) (*entity.Order, error) {
+	var orderModel models.Order
+	if err := dbFromContext(ctx, r.db).Unscoped().
+		Preload("OrderItems", orderItemsByPosition).
+		Where("deleted_at IS NOT NULL").
+		First(&orderModel, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil, nil when not found
+		}
+		return nil, err
+	}
+
+	return orderModel.ToEntity(), nil
+}
+
+// FindDeletedBefore retrieves up to limit orders soft-deleted before the given time, oldest first
+func (r *GormOrderRepository) FindDeletedBefore(ctx context.Context, before time.Time, limit int) ([]entity.Order, error) {
+	var orderModels []models.Order
+	if err := dbFromContext(ctx, r.db).Unscoped().
+		Preload("OrderItems", orderItemsByPosition).
+		Where("deleted_at < ?", before).
+		Order("deleted_at").
+		Limit(limit).
+		Find(&orderModels).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]entity.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = *model.ToEntity()
+	}
+
+	return orders, nil
+}
+
+// FindInStatusBefore retrieves up to limit orders that entered status before the given time,
+// oldest first. The time an order entered its status comes from its status history, falling
+// back to its creation time.
+func (r *GormOrderRepository) FindInStatusBefore(ctx context.Context, status string, before time.Time, unflaggedRule string, limit int) ([]entity.Order, error) {
+	enteredAt := `COALESCE((SELECT max(h.changed_at) FROM order_status_history h
+		WHERE h.order_id = orders.id AND h.to_status = orders.status), orders.created_at)`
+
+	query := dbFromContext(ctx, r.db).
+		Preload("OrderItems", orderItemsByPosition).
+		Where("status = ?", status).
+		Where(enteredAt+" < ?", before)
+	if unflaggedRule != "" {
+		query = query.Where("NOT EXISTS (SELECT 1 FROM order_flags f WHERE f.order_id = orders.id AND f.rule = ?)", unflaggedRule)
+	}
+
+	var orderModels []models.Order
+	if err := query.Order(enteredAt).Limit(limit).Find(&orderModels).Error; err != nil {
+		return nil, err
+	}
+
+	orders := make([]entity.Order, len(orderModels))
+	for i, model := range orderModels {
+		orders[i] = *model.ToEntity()
+	}
+
+	return orders, nil
+}
+
+// FindFlags retrieves the flags of an order, oldest first
+func (r *GormOrderRepository) FindFlags(ctx context.Context, orderID string) ([]entity.OrderFlag, error) {
+	var flagModels []models.OrderFlag
+	if err := dbFromContext(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("flagged_at, id").
+		Find(&flagModels).Error; err != nil {
+		return nil, err
+	}
+
+	flags := make([]entity.OrderFlag, len(flagModels))
+	for i, model := range flagModels {
+		flags[i] = *model.ToEntity()
+	}
+
+	return flags, nil
+}
+
+// FindFlagsByOrderIDs retrieves the flags of the given orders keyed by order ID, oldest first
+func (r *GormOrderRepository) FindFlagsByOrderIDs(ctx context.Context, orderIDs []string) (map[string][]entity.OrderFlag, error) {
+	var flagModels []models.OrderFlag
+	if err := dbFromContext(ctx, r.db).
+		Where("order_id IN ?", orderIDs).
+		Order("flagged_at, id").
+		Find(&flagModels).Error; err != nil {
+		return nil, err
+	}
+
+	flags := make(map[string][]entity.OrderFlag)
+	for _, model := range flagModels {
+		flags[model.OrderID] = append(flags[model.OrderID], *model.ToEntity())
+	}
+
+	return flags, nil
+}
+
+// Flag flags an order unless the rule already flagged it, and writes the events in the same
+// transaction when the flag is new
+func (r *GormOrderRepository) Flag(ctx context.Context, flag *entity.OrderFlag, events []entity.OrderEvent) (bool, error) {
+	flagModel := models.OrderFlag{}
+	flagModel.FromEntity(flag)
+
+	created := false
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&flagModel)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return nil
+		}
+		created = true
+		return createOutboxEvents(tx, events)
+	})
+	return created, err
+}
+
+// FindStatusHistory retrieves the status changes of an order, oldest first
+func (r *GormOrderRepository) FindStatusHistory(ctx context.Context, orderID string) ([]entity.OrderStatusChange, error) {
+	var historyModels []models.OrderStatusHistory
+	if err := dbFromContext(ctx, r.db).
+		Where("order_id = ?", orderID).
+		Order("changed_at, id").
+		Find(&historyModels).Error; err != nil {
+		return nil, err
+	}
+
+	history := make([]entity.OrderStatusChange, len(historyModels))
+	for i, model := range historyModels {
+		history[i] = *model.ToEntity()
+	}
+
+	return history, nil
+}
+
+// FindStatusHistoryByOrderIDs retrieves the status changes of the given orders keyed by order ID, oldest first
+func (r *GormOrderRepository) FindStatusHistoryByOrderIDs(ctx context.Context, orderIDs []string) (map[string][]entity.OrderStatusChange, error) {
+	var historyModels []models.OrderStatusHistory
+	if err := dbFromContext(ctx, r.db).
+		Where("order_id IN ?", orderIDs).
+		Order("changed_at, id").
+		Find(&historyModels).Error; err != nil {
+		return nil, err
+	}
+
+	history := make(map[string][]entity.OrderStatusChange)
+	for _, model := range historyModels {
+		history[model.OrderID] = append(history[model.OrderID], *model.ToEntity())
+	}
+
+	return history, nil
+}
+
+// NextOrderNumber returns the next value of the order number sequence
+func (r *GormOrderRepository) NextOrderNumber(ctx context.Context) (int64, error) {
+	var number int64
+	if err := dbFromContext(ctx, r.db).Raw("SELECT nextval(?)", models.OrderNumberSequence).Scan(&number).Error; err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+// Create creates a new order with its items, and records its initial status and events in the same transaction
+func (r *GormOrderRepository) Create(ctx context.Context, order *entity.Order, change *entity.OrderStatusChange, events []entity.OrderEvent) error {
 	orderModel := models.Order{}
 	orderModel.FromEntity(order)
 
-	return r.db.WithContext(ctx).Create(&orderModel).Error
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(&orderModel).Error; err != nil {
+			if errors.Is(err, gorm.ErrDuplicatedKey) {
+				return fmt.Errorf("%w: id %s or orderId %s is already used", entity.ErrDuplicateOrder, order.ID, order.OrderID)
+			}
+			return err
+		}
+		if err := createStatusChange(tx, change); err != nil {
+			return err
+		}
+		return createOutboxEvents(tx, events)
+	})
 }
 
-// Update updates an existing order
-func (r *GormOrderRepository) Update(ctx context.Context, order *entity.Order) error {
+// Update updates an existing order and replaces its items with the ones on the entity.
+// The row is only written while it still has the version on the entity, which is then
+// incremented; otherwise ErrOrderVersionConflict is returned. Items are immutable, so
+// items that are kept are left untouched. The status change, if any, and the events are
+// recorded in the same transaction.
+func (r *GormOrderRepository) Update(ctx context.Context, order *entity.Order, change *entity.OrderStatusChange, events []entity.OrderEvent) error {
 	orderModel := models.Order{}
 	orderModel.FromEntity(order)
+	orderModel.Version = order.Version + 1
+
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Model(&orderModel).
+			Where("version = ?", order.Version).
+			Select("*").
+			Omit(clause.Associations).
+			Updates(&orderModel)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s is no longer at version %d", entity.ErrOrderVersionConflict, order.ID, order.Version)
+		}
+		if err := createStatusChange(tx, change); err != nil {
+			return err
+		}
+		if err := createOutboxEvents(tx, events); err != nil {
+			return err
+		}
+
+		itemIDs := make([]string, len(orderModel.OrderItems))
+		for i, item := range orderModel.OrderItems {
+			itemIDs[i] = item.ID
+		}
+		stale := tx.Where("order_id = ?", orderModel.ID)
+		if len(itemIDs) > 0 {
+			stale = stale.Where("id NOT IN ?", itemIDs)
+		}
+		if err := stale.Delete(&models.OrderItem{}).Error; err != nil {
+			return err
+		}
+
+		if len(orderModel.OrderItems) == 0 {
+			return nil
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&orderModel.OrderItems).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	order.Version = orderModel.Version
+	order.UpdatedAt = orderModel.UpdatedAt
+	return nil
+}
+
+// createStatusChange inserts a status history row unless change is nil
+func createStatusChange(tx *gorm.DB, change *entity.OrderStatusChange) error {
+	if change == nil {
+		return nil
+	}
+
+	historyModel := models.OrderStatusHistory{}
+	historyModel.FromEntity(change)
+	return tx.Create(&historyModel).Error
+}
 
-	return r.db.WithContext(ctx).Save(&orderModel).Error
+// createOutboxEvents inserts events into the outbox
+func createOutboxEvents(tx *gorm.DB, events []entity.OrderEvent) error {
+	if len(events) == 0 {
+		return nil
+	}
+
+	outboxModels := make([]models.OutboxEvent, len(events))
+	for i := range events {
+		if err := outboxModels[i].FromOrderEvent(&events[i]); err != nil {
+			return err
+		}
+	}
+	return tx.Create(&outboxModels).Error
+}
+
+// UpdateCustomerDetails writes the customer name and email on the entity to the order, which
+// may be soft-deleted, and writes the events in the same transaction. Like Update, it requires
+// and increments the version on the entity.
+func (r *GormOrderRepository) UpdateCustomerDetails(ctx context.Context, order *entity.Order, events []entity.OrderEvent) error {
+	version := order.Version + 1
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Model(&models.Order{}).
+			Where("id = ? AND version = ?", order.ID, order.Version).
+			UpdateColumns(map[string]any{
+				"customer_name":  order.CustomerName,
+				"customer_email": order.CustomerEmail,
+				"updated_at":     order.UpdatedAt,
+				"version":        version,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s is no longer at version %d", entity.ErrOrderVersionConflict, order.ID, order.Version)
+		}
+		return createOutboxEvents(tx, events)
+	})
+	if err != nil {
+		return err
+	}
+
+	order.Version = version
+	return nil
+}
+
+// Delete soft-deletes an order at the time on the entity and writes the events in the same
+// transaction. Like Update, it requires and increments the version on the entity.
+func (r *GormOrderRepository) Delete(ctx context.Context, order *entity.Order, events []entity.OrderEvent) error {
+	return r.setDeletedAt(ctx, order, order.DeletedAt, "deleted_at IS NULL", events)
+}
+
+// Restore clears the deletion time of a soft-deleted order and writes the events in the
+// same transaction. Like Update, it requires and increments the version on the entity.
+func (r *GormOrderRepository) Restore(ctx context.Context, order *entity.Order, events []entity.OrderEvent) error {
+	return r.setDeletedAt(ctx, order, nil, "deleted_at IS NOT NULL", events)
 }
 
-// Delete deletes an order by its ID
-func (r *GormOrderRepository) Delete(ctx context.Context, id string) error {
-	return r.db.WithContext(ctx).Delete(&models.Order{}, "id = ?", id).Error
+// setDeletedAt moves an order in or out of the trash when it is in the expected state
+func (r *GormOrderRepository) setDeletedAt(ctx context.Context, order *entity.Order, deletedAt *time.Time, state string, events []entity.OrderEvent) error {
+	version := order.Version + 1
+	err := dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().
+			Model(&models.Order{}).
+			Where("id = ? AND version = ?", order.ID, order.Version).
+			Where(state).
+			UpdateColumns(map[string]any{
+				"deleted_at": deletedAt,
+				"updated_at": order.UpdatedAt,
+				"version":    version,
+			})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s is no longer at version %d", entity.ErrOrderVersionConflict, order.ID, order.Version)
+		}
+		return createOutboxEvents(tx, events)
+	})
+	if err != nil {
+		return err
+	}
+
+	order.Version = version
+	order.DeletedAt = deletedAt
+	return nil
+}
+
+// Purge permanently removes a soft-deleted order; its items go with it through the foreign
+// key, while its status history is kept. The events are written in the same transaction.
+func (r *GormOrderRepository) Purge(ctx context.Context, id string, events []entity.OrderEvent) error {
+	return dbFromContext(ctx, r.db).Transaction(func(tx *gorm.DB) error {
+		result := tx.Unscoped().Where("deleted_at IS NOT NULL").Delete(&models.Order{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return fmt.Errorf("%w: %s", entity.ErrOrderNotDeleted, id)
+		}
+		return createOutboxEvents(tx, events)
+	})
 }
@@ -0,0 +1,85 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
+	"gorm.io/gorm"
+)
+
+// GormOutboxRepository implements the OutboxRepository interface using GORM
+type GormOutboxRepository struct {
+	db *gorm.DB
+}
+
+// NewGormOutboxRepository creates a new GormOutboxRepository
+func NewGormOutboxRepository(db *gorm.DB) repository.OutboxRepository {
+	return &GormOutboxRepository{
+		db: db,
+	}
+}
+
+// DeleteBefore deletes events that occurred before the given time and returns how many were deleted
+func (r *GormOutboxRepository) DeleteBefore(ctx context.Context, before time.Time) (int64, error) {
+	result := r.db.WithContext(ctx).Where("occurred_at < ?", before).Delete(&models.OutboxEvent{})
+	return result.RowsAffected, result.Error
+}
+
+// settledOutbox selects the outbox events of transactions older than every running one, whose
+// positions are final: no transaction can still write an event before them
+const settledOutbox = "txid < txid_snapshot_xmin(txid_current_snapshot())"
+
+// FindAfter returns up to limit order events after a position, in outbox order
+func (r *GormOutboxRepository) FindAfter(ctx context.Context, after entity.OutboxPosition, limit int) ([]entity.OutboxEntry, error) {
+	var rows []models.OutboxEvent
+	if err := r.db.WithContext(ctx).
+		Where("aggregatetype = ?", models.OrderAggregateType).
+		Where(`(txid, "sequence") > (?, ?)`, after.TxID, after.Sequence).
+		Where(settledOutbox).
+		Order(`txid, "sequence"`).
+		Limit(limit).
+		Find(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	entries := make([]entity.OutboxEntry, len(rows))
+	for i := range rows {
+		entry, err := rows[i].ToOrderEntry()
+		if err != nil {
+			return nil, err
+		}
+		entries[i] = *entry
+	}
+	return entries, nil
+}
+
+// Head returns the position of the last settled order event, or the zero position when there is none
+func (r *GormOutboxRepository) Head(ctx context.Context) (entity.OutboxPosition, error) {
+	var row models.OutboxEvent
+	err := r.db.WithContext(ctx).
+		Select(`txid, "sequence"`).
+		Where("aggregatetype = ?", models.OrderAggregateType).
+		Where(settledOutbox).
+		Order(`txid DESC, "sequence" DESC`).
+		Take(&row).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return entity.OutboxPosition{}, nil
+	}
+	if err != nil {
+		return entity.OutboxPosition{}, err
+	}
+	return entity.OutboxPosition{TxID: row.TxID, Sequence: row.Sequence}, nil
+}
+
+// Exists reports whether the outbox still holds the order event at a position
+func (r *GormOutboxRepository) Exists(ctx context.Context, position entity.OutboxPosition) (bool, error) {
+	var count int64
+	err := r.db.WithContext(ctx).Model(&models.OutboxEvent{}).
+		Where("aggregatetype = ? AND txid = ? AND \"sequence\" = ?", models.OrderAggregateType, position.TxID, position.Sequence).
+		Count(&count).Error
+	return count > 0, err
+}
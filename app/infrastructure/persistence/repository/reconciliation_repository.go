@@ -0,0 +1,67 @@
+package repository
+
+import (
+	"context"
+	"errors"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
+	"gorm.io/gorm"
+)
+
+// GormReconciliationRepository implements the ReconciliationRepository interface using GORM
+type GormReconciliationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormReconciliationRepository creates a new GormReconciliationRepository
+func NewGormReconciliationRepository(db *gorm.DB) repository.ReconciliationRepository {
+	return &GormReconciliationRepository{
+		db: db,
+	}
+}
+
+// FindAll retrieves all reconciliation reports, newest first
+func (r *GormReconciliationRepository) FindAll(ctx context.Context) ([]entity.ReconciliationReport, error) {
+	var reportModels []models.ReconciliationReport
+	if err := r.db.WithContext(ctx).Order("started_at DESC").Find(&reportModels).Error; err != nil {
+		return nil, err
+	}
+
+	reports := make([]entity.ReconciliationReport, len(reportModels))
+	for i, model := range reportModels {
+		reports[i] = *model.ToEntity()
+	}
+
+	return reports, nil
+}
+
+// FindByID retrieves a reconciliation report by its ID
+func (r *GormReconciliationRepository) FindByID(ctx context.Context, id string) (*entity.ReconciliationReport, error) {
+	var reportModel models.ReconciliationReport
+	if err := r.db.WithContext(ctx).First(&reportModel, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil, nil when not found
+		}
+		return nil, err
+	}
+
+	return reportModel.ToEntity(), nil
+}
+
+// Create creates a new reconciliation report
+func (r *GormReconciliationRepository) Create(ctx context.Context, report *entity.ReconciliationReport) error {
+	reportModel := models.ReconciliationReport{}
+	reportModel.FromEntity(report)
+
+	return r.db.WithContext(ctx).Create(&reportModel).Error
+}
+
+// Update updates an existing reconciliation report
+func (r *GormReconciliationRepository) Update(ctx context.Context, report *entity.ReconciliationReport) error {
+	reportModel := models.ReconciliationReport{}
+	reportModel.FromEntity(report)
+
+	return r.db.WithContext(ctx).Save(&reportModel).Error
+}
@@ -0,0 +1,92 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"gorm.io/gorm"
+)
+
+// slotQuery selects replication slots with the WAL retained behind their restart LSN
+const slotQuery = `SELECT slot_name AS name, COALESCE(plugin, '') AS plugin, slot_type, COALESCE(database, '') AS database,
+	active, active_pid, COALESCE(restart_lsn::text, '') AS restart_lsn,
+	COALESCE(confirmed_flush_lsn::text, '') AS confirmed_flush_lsn,
+	COALESCE(pg_wal_lsn_diff(pg_current_wal_lsn(), restart_lsn), 0)::bigint AS retained_wal_bytes,
+	COALESCE(wal_status, '') AS wal_status
+	FROM pg_replication_slots`
+
+// replicationSlotRow is a row of slotQuery
+type replicationSlotRow struct {
+	Name              string `gorm:"column:name"`
+	Plugin            string `gorm:"column:plugin"`
+	SlotType          string `gorm:"column:slot_type"`
+	Database          string `gorm:"column:database"`
+	Active            bool   `gorm:"column:active"`
+	ActivePID         *int   `gorm:"column:active_pid"`
+	RestartLSN        string `gorm:"column:restart_lsn"`
+	ConfirmedFlushLSN string `gorm:"column:confirmed_flush_lsn"`
+	RetainedWALBytes  int64  `gorm:"column:retained_wal_bytes"`
+	WALStatus         string `gorm:"column:wal_status"`
+}
+
+// toEntity converts the row to a domain entity
+func (r *replicationSlotRow) toEntity() *entity.ReplicationSlot {
+	return &entity.ReplicationSlot{
+		Name:              r.Name,
+		Plugin:            r.Plugin,
+		SlotType:          r.SlotType,
+		Database:          r.Database,
+		Active:            r.Active,
+		ActivePID:         r.ActivePID,
+		RestartLSN:        r.RestartLSN,
+		ConfirmedFlushLSN: r.ConfirmedFlushLSN,
+		RetainedWALBytes:  r.RetainedWALBytes,
+		WALStatus:         r.WALStatus,
+	}
+}
+
+// GormReplicationRepository implements the ReplicationRepository interface using GORM
+type GormReplicationRepository struct {
+	db *gorm.DB
+}
+
+// NewGormReplicationRepository creates a new GormReplicationRepository
+func NewGormReplicationRepository(db *gorm.DB) repository.ReplicationRepository {
+	return &GormReplicationRepository{
+		db: db,
+	}
+}
+
+// FindSlots retrieves all replication slots
+func (r *GormReplicationRepository) FindSlots(ctx context.Context) ([]entity.ReplicationSlot, error) {
+	var rows []replicationSlotRow
+	if err := r.db.WithContext(ctx).Raw(slotQuery + " ORDER BY slot_name").Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+
+	slots := make([]entity.ReplicationSlot, len(rows))
+	for i, row := range rows {
+		slots[i] = *row.toEntity()
+	}
+
+	return slots, nil
+}
+
+// FindSlotByName retrieves a replication slot by its name
+func (r *GormReplicationRepository) FindSlotByName(ctx context.Context, name string) (*entity.ReplicationSlot, error) {
+	var rows []replicationSlotRow
+	if err := r.db.WithContext(ctx).Raw(slotQuery+" WHERE slot_name = ?", name).Scan(&rows).Error; err != nil {
+		return nil, err
+	}
+	if len(rows) == 0 {
+		return nil, nil // Return nil, nil when not found
+	}
+
+	return rows[0].toEntity(), nil
+}
+
+// DropSlot drops a replication slot by its name
+func (r *GormReplicationRepository) DropSlot(ctx context.Context, name string) error {
+	return r.db.WithContext(ctx).Exec("SELECT pg_drop_replication_slot(?)", name).Error
+}
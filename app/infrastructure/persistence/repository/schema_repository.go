@@ -0,0 +1,40 @@
+package repository
+
+import (
+	"context"
+	"strings"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"gorm.io/gorm"
+)
+
+// GormSchemaRepository implements the SchemaRepository interface using GORM
+type GormSchemaRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSchemaRepository creates a new GormSchemaRepository
+func NewGormSchemaRepository(db *gorm.DB) repository.SchemaRepository {
+	return &GormSchemaRepository{
+		db: db,
+	}
+}
+
+// FindColumns retrieves the columns of a table in ordinal order
+func (r *GormSchemaRepository) FindColumns(ctx context.Context, table string) ([]entity.Column, error) {
+	schema, name, found := strings.Cut(table, ".")
+	if !found {
+		schema, name = "public", table
+	}
+
+	var columns []entity.Column
+	if err := r.db.WithContext(ctx).Raw(`SELECT column_name AS name, data_type
+		FROM information_schema.columns
+		WHERE table_schema = ? AND table_name = ?
+		ORDER BY ordinal_position`, schema, name).Scan(&columns).Error; err != nil {
+		return nil, err
+	}
+
+	return columns, nil
+}
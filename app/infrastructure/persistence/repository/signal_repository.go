@@ -0,0 +1,121 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/models"
+	"gorm.io/gorm"
+)
+
+// GormSignalRepository implements the SignalRepository interface using GORM
+type GormSignalRepository struct {
+	db *gorm.DB
+}
+
+// NewGormSignalRepository creates a new GormSignalRepository
+func NewGormSignalRepository(db *gorm.DB) repository.SignalRepository {
+	return &GormSignalRepository{
+		db: db,
+	}
+}
+
+// CreateSnapshotRequest stores a new snapshot request
+func (r *GormSignalRepository) CreateSnapshotRequest(ctx context.Context, request *entity.SnapshotRequest) error {
+	requestModel := models.SnapshotRequest{}
+	requestModel.FromEntity(request)
+
+	return r.db.WithContext(ctx).Create(&requestModel).Error
+}
+
+// SignalSnapshotRequest updates a snapshot request and writes its signal in one transaction
+func (r *GormSignalRepository) SignalSnapshotRequest(ctx context.Context, request *entity.SnapshotRequest, signal *entity.Signal) error {
+	requestModel := models.SnapshotRequest{}
+	requestModel.FromEntity(request)
+	signalModel := models.DebeziumSignal{}
+	signalModel.FromEntity(signal)
+
+	return r.db.WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Save(&requestModel).Error; err != nil {
+			return err
+		}
+		return tx.Create(&signalModel).Error
+	})
+}
+
+// FindSnapshotRequestByID retrieves a snapshot request by its ID
+func (r *GormSignalRepository) FindSnapshotRequestByID(ctx context.Context, id string) (*entity.SnapshotRequest, error) {
+	var requestModel models.SnapshotRequest
+	if err := r.db.WithContext(ctx).First(&requestModel, "id = ?", id).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil // Return nil, nil when not found
+		}
+		return nil, err
+	}
+
+	return requestModel.ToEntity(), nil
+}
+
+// FindAllSnapshotRequests retrieves all snapshot requests, newest first
+func (r *GormSignalRepository) FindAllSnapshotRequests(ctx context.Context) ([]entity.SnapshotRequest, error) {
+	var requestModels []models.SnapshotRequest
+	if err := r.db.WithContext(ctx).Order("created_at DESC").Find(&requestModels).Error; err != nil {
+		return nil, err
+	}
+
+	return snapshotRequestsToEntities(requestModels), nil
+}
+
+// FindSnapshotRequestsByStatus retrieves snapshot requests having one of the given statuses
+func (r *GormSignalRepository) FindSnapshotRequestsByStatus(ctx context.Context, statuses ...string) ([]entity.SnapshotRequest, error) {
+	var requestModels []models.SnapshotRequest
+	if err := r.db.WithContext(ctx).Where("status IN ?", statuses).Order("created_at").Find(&requestModels).Error; err != nil {
+		return nil, err
+	}
+
+	return snapshotRequestsToEntities(requestModels), nil
+}
+
+// UpdateSnapshotRequest updates an existing snapshot request
+func (r *GormSignalRepository) UpdateSnapshotRequest(ctx context.Context, request *entity.SnapshotRequest) error {
+	requestModel := models.SnapshotRequest{}
+	requestModel.FromEntity(request)
+
+	return r.db.WithContext(ctx).Save(&requestModel).Error
+}
+
+// FindSignalsAfter retrieves signals of the given types written after the signal with the
+// given ID, oldest first; none when that signal does not exist
+func (r *GormSignalRepository) FindSignalsAfter(ctx context.Context, signalID string, types ...string) ([]entity.Signal, error) {
+	db := r.db.WithContext(ctx)
+	var signalModels []models.DebeziumSignal
+	if err := db.
+		Where("created_at > (?) AND type IN ?", db.Model(&models.DebeziumSignal{}).Select("created_at").Where("id = ?", signalID), types).
+		Order("created_at").
+		Find(&signalModels).Error; err != nil {
+		return nil, err
+	}
+
+	signals := make([]entity.Signal, len(signalModels))
+	for i, model := range signalModels {
+		signals[i] = *model.ToEntity()
+	}
+
+	return signals, nil
+}
+
+// DeleteSignalsBefore deletes signals written before the given time
+func (r *GormSignalRepository) DeleteSignalsBefore(ctx context.Context, before time.Time) error {
+	return r.db.WithContext(ctx).Where("created_at < ?", before).Delete(&models.DebeziumSignal{}).Error
+}
+
+func snapshotRequestsToEntities(requestModels []models.SnapshotRequest) []entity.SnapshotRequest {
+	requests := make([]entity.SnapshotRequest, len(requestModels))
+	for i, model := range requestModels {
+		requests[i] = *model.ToEntity()
+	}
+	return requests
+}
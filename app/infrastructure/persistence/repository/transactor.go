@@ -0,0 +1,39 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+	"gorm.io/gorm"
+)
+
+// txKey is the context key of the transaction started by GormTransactor
+type txKey struct{}
+
+// GormTransactor implements the Transactor interface using GORM
+type GormTransactor struct {
+	db *gorm.DB
+}
+
+// NewGormTransactor creates a new GormTransactor
+func NewGormTransactor(db *gorm.DB) repository.Transactor {
+	return &GormTransactor{
+		db: db,
+	}
+}
+
+// InTransaction calls fn with a context carrying a transaction, committed when fn returns nil
+func (t *GormTransactor) InTransaction(ctx context.Context, fn func(ctx context.Context) error) error {
+	return dbFromContext(ctx, t.db).Transaction(func(tx *gorm.DB) error {
+		return fn(context.WithValue(ctx, txKey{}, tx))
+	})
+}
+
+// dbFromContext returns the transaction carried by ctx, or db when there is none. Transactions
+// opened on the result nest as savepoints.
+func dbFromContext(ctx context.Context, db *gorm.DB) *gorm.DB {
+	if tx, ok := ctx.Value(txKey{}).(*gorm.DB); ok {
+		return tx.WithContext(ctx)
+	}
+	return db.WithContext(ctx)
+}
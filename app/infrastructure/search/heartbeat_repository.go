@@ -0,0 +1,63 @@
+package search
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// heartbeatDocument is the heartbeat row as written to Elasticsearch by the sink connector
+type heartbeatDocument struct {
+	ID     string    `json:"id"`
+	Seq    int64     `json:"seq"`
+	BeatAt time.Time `json:"beat_at"`
+}
+
+// ESHeartbeatRepository implements the HeartbeatSearchRepository interface using Elasticsearch
+type ESHeartbeatRepository struct {
+	es    *elasticsearch.Client
+	index string
+}
+
+// NewESHeartbeatRepository creates a new ESHeartbeatRepository
+func NewESHeartbeatRepository(es *elasticsearch.Client, index string) repository.HeartbeatSearchRepository {
+	return &ESHeartbeatRepository{
+		es:    es,
+		index: index,
+	}
+}
+
+// FindByID retrieves the replicated heartbeat by its ID
+func (r *ESHeartbeatRepository) FindByID(ctx context.Context, id string) (*entity.Heartbeat, error) {
+	res, err := r.es.Get(r.index, id, r.es.Get.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, nil // Return nil, nil when not found
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("elasticsearch get %s/%s: %s", r.index, id, res.String())
+	}
+
+	var body struct {
+		Source heartbeatDocument `json:"_source"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&body); err != nil {
+		return nil, err
+	}
+
+	return &entity.Heartbeat{
+		ID:     body.Source.ID,
+		Seq:    body.Source.Seq,
+		BeatAt: body.Source.BeatAt,
+	}, nil
+}
@@ -0,0 +1,171 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// ESMappingRepository implements the MappingRepository interface using Elasticsearch
+type ESMappingRepository struct {
+	es *elasticsearch.Client
+}
+
+// NewESMappingRepository creates a new ESMappingRepository
+func NewESMappingRepository(es *elasticsearch.Client) repository.MappingRepository {
+	return &ESMappingRepository{
+		es: es,
+	}
+}
+
+// FindFieldTypes retrieves the top-level field types of the index; exists is false when the index is missing
+func (r *ESMappingRepository) FindFieldTypes(ctx context.Context, index string) (map[string]string, bool, error) {
+	res, err := r.es.Indices.GetMapping(r.es.Indices.GetMapping.WithIndex(index), r.es.Indices.GetMapping.WithContext(ctx))
+	if err != nil {
+		return nil, false, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return nil, false, nil
+	}
+	if res.IsError() {
+		return nil, false, fmt.Errorf("elasticsearch get mapping %s: %s", index, res.String())
+	}
+
+	var result map[string]struct {
+		Mappings struct {
+			Properties map[string]struct {
+				Type       string          `json:"type"`
+				Properties json.RawMessage `json:"properties"`
+			} `json:"properties"`
+		} `json:"mappings"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, false, err
+	}
+
+	types := make(map[string]string)
+	for _, mapping := range result {
+		for field, property := range mapping.Mappings.Properties {
+			switch {
+			case property.Type != "":
+				types[field] = property.Type
+			case property.Properties != nil:
+				types[field] = "object"
+			}
+		}
+	}
+
+	return types, true, nil
+}
+
+// CreateIndex creates the index with the given field types
+func (r *ESMappingRepository) CreateIndex(ctx context.Context, index string, types map[string]string) error {
+	body, err := json.Marshal(map[string]any{
+		"mappings": map[string]any{"properties": fieldProperties(types)},
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := r.es.Indices.Create(index, r.es.Indices.Create.WithBody(bytes.NewReader(body)), r.es.Indices.Create.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch create index %s: %s", index, res.String())
+	}
+	return nil
+}
+
+// AddFields adds new fields with the given types to the index mapping
+func (r *ESMappingRepository) AddFields(ctx context.Context, index string, types map[string]string) error {
+	body, err := json.Marshal(map[string]any{"properties": fieldProperties(types)})
+	if err != nil {
+		return err
+	}
+
+	res, err := r.es.Indices.PutMapping([]string{index}, bytes.NewReader(body), r.es.Indices.PutMapping.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch put mapping %s: %s", index, res.String())
+	}
+	return nil
+}
+
+// EnsureJSONPipeline makes the index parse the given JSON string fields on ingest.
+// The pipeline is set as the default pipeline so documents written by the sink connector pass through it.
+func (r *ESMappingRepository) EnsureJSONPipeline(ctx context.Context, index string, fields []string) error {
+	if len(fields) == 0 {
+		return nil
+	}
+
+	processors := make([]any, len(fields))
+	for i, field := range fields {
+		processors[i] = map[string]any{
+			"json": map[string]any{
+				"field": field,
+				"if":    fmt.Sprintf("ctx[%q] instanceof String", field),
+			},
+		}
+	}
+	pipeline := index + "-json"
+	body, err := json.Marshal(map[string]any{
+		"description": "Parses JSON columns emitted as strings by Debezium",
+		"processors":  processors,
+	})
+	if err != nil {
+		return err
+	}
+
+	res, err := r.es.Ingest.PutPipeline(pipeline, bytes.NewReader(body), r.es.Ingest.PutPipeline.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch put pipeline %s: %s", pipeline, res.String())
+	}
+
+	settings, err := json.Marshal(map[string]any{"index": map[string]any{"default_pipeline": pipeline}})
+	if err != nil {
+		return err
+	}
+	res, err = r.es.Indices.PutSettings(bytes.NewReader(settings), r.es.Indices.PutSettings.WithIndex(index), r.es.Indices.PutSettings.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch put settings %s: %s", index, res.String())
+	}
+
+	return nil
+}
+
+// fieldProperties builds mapping properties, giving text fields the keyword sub-field dynamic mapping would add
+func fieldProperties(types map[string]string) map[string]any {
+	properties := make(map[string]any, len(types))
+	for field, fieldType := range types {
+		property := map[string]any{"type": fieldType}
+		if fieldType == "text" {
+			property["fields"] = map[string]any{
+				"keyword": map[string]any{"type": "keyword", "ignore_above": 256},
+			}
+		}
+		properties[field] = property
+	}
+	return properties
+}
@@ -0,0 +1,369 @@
+package search
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/elastic/go-elasticsearch/v8"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// orderDocument is the orders row as written to Elasticsearch by the sink connector
+type orderDocument struct {
+	ID            string          `json:"id"`
+	OrderID       string          `json:"order_id"`
+	CustomerID    string          `json:"customer_id"`
+	CustomerName  string          `json:"customer_name"`
+	CustomerEmail string          `json:"customer_email"`
+	Status        string          `json:"status"`
+	TotalAmount   int64           `json:"total_amount"`
+	Currency      string          `json:"currency"`
+	Items         json.RawMessage `json:"items"`
+	Version       int64           `json:"version"`
+	CreatedAt     *time.Time      `json:"created_at"`
+	UpdatedAt     *time.Time      `json:"updated_at"`
+	DeletedAt     *time.Time      `json:"deleted_at"`
+}
+
+// orderItemDocument is an element of the nested items field
+type orderItemDocument struct {
+	ID              string `json:"id"`
+	SKU             string `json:"sku"`
+	Quantity        int    `json:"quantity"`
+	UnitPriceAmount int64  `json:"unit_price_amount"`
+	LineTotalAmount int64  `json:"line_total_amount"`
+	Currency        string `json:"currency"`
+}
+
+// toEntity converts the document to a domain entity
+func (d *orderDocument) toEntity() entity.Order {
+	order := entity.Order{
+		ID:            d.ID,
+		OrderID:       d.OrderID,
+		CustomerID:    d.CustomerID,
+		CustomerName:  d.CustomerName,
+		CustomerEmail: d.CustomerEmail,
+		Status:        d.Status,
+		Total:         entity.Money{Amount: d.TotalAmount, Currency: d.Currency},
+		Version:       d.Version,
+	}
+	for _, item := range d.items() {
+		order.Items = append(order.Items, entity.OrderItem{
+			ID:        item.ID,
+			SKU:       item.SKU,
+			Quantity:  item.Quantity,
+			UnitPrice: entity.Money{Amount: item.UnitPriceAmount, Currency: item.Currency},
+			LineTotal: entity.Money{Amount: item.LineTotalAmount, Currency: item.Currency},
+		})
+	}
+	if d.CreatedAt != nil {
+		order.CreatedAt = *d.CreatedAt
+	}
+	if d.UpdatedAt != nil {
+		order.UpdatedAt = *d.UpdatedAt
+	}
+	order.DeletedAt = d.DeletedAt
+	return order
+}
+
+// items decodes the items field, which is a JSON string when the ingest pipeline did not parse it
+func (d *orderDocument) items() []orderItemDocument {
+	raw := []byte(d.Items)
+	var encoded string
+	if json.Unmarshal(raw, &encoded) == nil {
+		raw = []byte(encoded)
+	}
+
+	var items []orderItemDocument
+	if err := json.Unmarshal(raw, &items); err != nil {
+		return nil
+	}
+	return items
+}
+
+// orderSortFields maps the sortable order fields to their document fields; text columns are
+// sorted on the keyword sub-field dynamic mapping adds
+var orderSortFields = map[string]string{
+	entity.OrderSortField.ID:         "id.keyword",
+	entity.OrderSortField.OrderID:    "order_id.keyword",
+	entity.OrderSortField.CustomerID: "customer_id.keyword",
+	entity.OrderSortField.Status:     "status.keyword",
+	entity.OrderSortField.CreatedAt:  "created_at",
+	entity.OrderSortField.UpdatedAt:  "updated_at",
+}
+
+// ESOrderRepository implements the OrderSearchRepository interface using Elasticsearch
+type ESOrderRepository struct {
+	es    *elasticsearch.Client
+	index string
+}
+
+// NewESOrderRepository creates a new ESOrderRepository
+func NewESOrderRepository(es *elasticsearch.Client, index string) repository.OrderSearchRepository {
+	return &ESOrderRepository{
+		es:    es,
+		index: index,
+	}
+}
+
+// Search retrieves the page of indexed live orders selected by query and the number of
+// orders matching it
+func (r *ESOrderRepository) Search(ctx context.Context, query entity.OrderQuery) ([]entity.Order, int64, error) {
+	field, ok := orderSortFields[query.Sort]
+	if !ok {
+		return nil, 0, fmt.Errorf("%w: cannot sort by %q", entity.ErrInvalidOrderQuery, query.Sort)
+	}
+	order := "asc"
+	if query.Descending {
+		order = "desc"
+	}
+
+	filters := []map[string]any{}
+	if len(query.Statuses) > 0 {
+		filters = append(filters, map[string]any{"terms": map[string]any{"status.keyword": query.Statuses}})
+	}
+	if query.CustomerID != "" {
+		filters = append(filters, map[string]any{"term": map[string]any{"customer_id.keyword": query.CustomerID}})
+	}
+	if r := timeRange(query.CreatedFrom, query.CreatedTo); r != nil {
+		filters = append(filters, map[string]any{"range": map[string]any{"created_at": r}})
+	}
+	if r := timeRange(query.UpdatedFrom, query.UpdatedTo); r != nil {
+		filters = append(filters, map[string]any{"range": map[string]any{"updated_at": r}})
+	}
+
+	body, err := json.Marshal(map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter":   filters,
+				"must_not": []map[string]any{{"exists": map[string]any{"field": "deleted_at"}}},
+			},
+		},
+		"sort": []map[string]any{
+			{field: map[string]any{"order": order}},
+			{"id.keyword": map[string]any{"order": order}},
+		},
+		"from":             query.Offset,
+		"size":             query.Limit,
+		"track_total_hits": true,
+	})
+	if err != nil {
+		return nil, 0, err
+	}
+
+	res, err := r.es.Search(
+		r.es.Search.WithContext(ctx),
+		r.es.Search.WithIndex(r.index),
+		r.es.Search.WithBody(bytes.NewReader(body)),
+	)
+	if err != nil {
+		return nil, 0, fmt.Errorf("%w: elasticsearch search %s: %w", entity.ErrUnavailable, r.index, err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return []entity.Order{}, 0, nil
+	}
+	if res.StatusCode == http.StatusTooManyRequests || res.StatusCode >= http.StatusInternalServerError {
+		return nil, 0, fmt.Errorf("%w: elasticsearch search %s: %s", entity.ErrUnavailable, r.index, res.String())
+	}
+	if res.IsError() {
+		return nil, 0, fmt.Errorf("elasticsearch search %s: %s", r.index, res.String())
+	}
+
+	var result struct {
+		Hits struct {
+			Total struct {
+				Value int64 `json:"value"`
+			} `json:"total"`
+			Hits []struct {
+				Source orderDocument `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, 0, err
+	}
+
+	orders := make([]entity.Order, len(result.Hits.Hits))
+	for i, hit := range result.Hits.Hits {
+		orders[i] = hit.Source.toEntity()
+	}
+
+	return orders, result.Hits.Total.Value, nil
+}
+
+// timeRange builds a range query including from and excluding to, or nil when both are unset
+func timeRange(from, to *time.Time) map[string]any {
+	if from == nil && to == nil {
+		return nil
+	}
+	r := map[string]any{}
+	if from != nil {
+		r["gte"] = from.Format(time.RFC3339Nano)
+	}
+	if to != nil {
+		r["lt"] = to.Format(time.RFC3339Nano)
+	}
+	return r
+}
+
+// FindByIDs retrieves the indexed orders for the given ids, keyed by ID; missing ids are absent
+func (r *ESOrderRepository) FindByIDs(ctx context.Context, ids []string) (map[string]entity.Order, error) {
+	orders := make(map[string]entity.Order, len(ids))
+	if len(ids) == 0 {
+		return orders, nil
+	}
+
+	body, err := json.Marshal(map[string][]string{"ids": ids})
+	if err != nil {
+		return nil, err
+	}
+	res, err := r.es.Mget(bytes.NewReader(body), r.es.Mget.WithIndex(r.index), r.es.Mget.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode == http.StatusNotFound {
+		return orders, nil
+	}
+	if res.IsError() {
+		return nil, fmt.Errorf("elasticsearch mget %s: %s", r.index, res.String())
+	}
+
+	var result struct {
+		Docs []struct {
+			ID     string        `json:"_id"`
+			Found  bool          `json:"found"`
+			Source orderDocument `json:"_source"`
+		} `json:"docs"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return nil, err
+	}
+
+	for _, doc := range result.Docs {
+		if doc.Found {
+			orders[doc.ID] = doc.Source.toEntity()
+		}
+	}
+
+	return orders, nil
+}
+
+// ScanIDs walks the IDs of all indexed orders in batches of at most batchSize
+func (r *ESOrderRepository) ScanIDs(ctx context.Context, batchSize int, fn func(ids []string) error) error {
+	res, err := r.es.Search(
+		r.es.Search.WithContext(ctx),
+		r.es.Search.WithIndex(r.index),
+		r.es.Search.WithScroll(time.Minute),
+		r.es.Search.WithSize(batchSize),
+		r.es.Search.WithSource("false"),
+		r.es.Search.WithSort("_doc"),
+	)
+	if err != nil {
+		return err
+	}
+
+	var scrollID string
+	defer func() {
+		if scrollID != "" {
+			if res, err := r.es.ClearScroll(r.es.ClearScroll.WithScrollID(scrollID)); err == nil {
+				res.Body.Close()
+			}
+		}
+	}()
+
+	for {
+		if res.StatusCode == http.StatusNotFound && scrollID == "" {
+			res.Body.Close()
+			return nil
+		}
+		if res.IsError() {
+			defer res.Body.Close()
+			return fmt.Errorf("elasticsearch scroll %s: %s", r.index, res.String())
+		}
+
+		var page struct {
+			ScrollID string `json:"_scroll_id"`
+			Hits     struct {
+				Hits []struct {
+					ID string `json:"_id"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		err := json.NewDecoder(res.Body).Decode(&page)
+		res.Body.Close()
+		if err != nil {
+			return err
+		}
+		scrollID = page.ScrollID
+
+		if len(page.Hits.Hits) == 0 {
+			return nil
+		}
+		ids := make([]string, len(page.Hits.Hits))
+		for i, hit := range page.Hits.Hits {
+			ids[i] = hit.ID
+		}
+		if err := fn(ids); err != nil {
+			return err
+		}
+
+		res, err = r.es.Scroll(
+			r.es.Scroll.WithContext(ctx),
+			r.es.Scroll.WithScrollID(scrollID),
+			r.es.Scroll.WithScroll(time.Minute),
+		)
+		if err != nil {
+			return err
+		}
+	}
+}
+
+// DeleteByIDs removes the indexed orders with the given ids
+func (r *ESOrderRepository) DeleteByIDs(ctx context.Context, ids []string) error {
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var body bytes.Buffer
+	for _, id := range ids {
+		action, err := json.Marshal(map[string]map[string]string{
+			"delete": {"_index": r.index, "_id": id},
+		})
+		if err != nil {
+			return err
+		}
+		body.Write(action)
+		body.WriteByte('\n')
+	}
+
+	res, err := r.es.Bulk(&body, r.es.Bulk.WithContext(ctx))
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.IsError() {
+		return fmt.Errorf("elasticsearch bulk delete %s: %s", r.index, res.String())
+	}
+
+	var result struct {
+		Errors bool `json:"errors"`
+	}
+	if err := json.NewDecoder(res.Body).Decode(&result); err != nil {
+		return err
+	}
+	if result.Errors {
+		return fmt.Errorf("elasticsearch bulk delete %s: some documents failed", r.index)
+	}
+
+	return nil
+}
@@ -0,0 +1,163 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// AdminHandler handles HTTP requests for operational endpoints
+type AdminHandler struct {
+	heartbeatService   *service.HeartbeatService
+	reconcileService   *service.ReconcileService
+	replicationService *service.ReplicationService
+	schemaService      *service.SchemaService
+	automationService  *service.AutomationService
+	orderIndex         string
+}
+
+// NewAdminHandler creates a new AdminHandler
+func NewAdminHandler(heartbeatService *service.HeartbeatService, reconcileService *service.ReconcileService,
+	replicationService *service.ReplicationService, schemaService *service.SchemaService,
+	automationService *service.AutomationService, orderIndex string) *AdminHandler {
+	return &AdminHandler{
+		heartbeatService:   heartbeatService,
+		reconcileService:   reconcileService,
+		replicationService: replicationService,
+		schemaService:      schemaService,
+		automationService:  automationService,
+		orderIndex:         orderIndex,
+	}
+}
+
+// GetReplicationLatency handles GET /api/admin/replication/latency
+func (h *AdminHandler) GetReplicationLatency(c *fiber.Ctx) error {
+	recent := c.QueryInt("recent", 20)
+	if recent < 0 {
+		return entity.NewFieldError(service.ErrInvalidLatencyReport, "recent", fmt.Sprintf("must not be negative, got %d", recent))
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Replication latency fetched successfully",
+		"data":    h.heartbeatService.Report(recent),
+	})
+}
+
+// StartReconciliation handles POST /api/admin/reconciliations
+func (h *AdminHandler) StartReconciliation(c *fiber.Ctx) error {
+	repair := c.QueryBool("repair", false)
+
+	report, err := h.reconcileService.StartReconcile(c.Context(), repair)
+	if err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Reconciliation started successfully",
+		"data":    report,
+	})
+}
+
+// GetAllReconciliations handles GET /api/admin/reconciliations
+func (h *AdminHandler) GetAllReconciliations(c *fiber.Ctx) error {
+	reports, err := h.reconcileService.GetAllReports(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reconciliations fetched successfully",
+		"data":    reports,
+		"count":   len(reports),
+	})
+}
+
+// GetReconciliation handles GET /api/admin/reconciliations/:id
+func (h *AdminHandler) GetReconciliation(c *fiber.Ctx) error {
+	id := c.Params("id")
+	report, err := h.reconcileService.GetReportByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Reconciliation fetched successfully",
+		"data":    report,
+	})
+}
+
+// GetReplicationSlots handles GET /api/admin/replication/slots
+func (h *AdminHandler) GetReplicationSlots(c *fiber.Ctx) error {
+	slots, err := h.replicationService.GetSlots(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Replication slots fetched successfully",
+		"data":    slots,
+		"count":   len(slots),
+	})
+}
+
+// DropReplicationSlot handles DELETE /api/admin/replication/slots/:name?confirm=:name
+func (h *AdminHandler) DropReplicationSlot(c *fiber.Ctx) error {
+	name := c.Params("name")
+
+	if err := h.replicationService.DropSlot(c.Context(), name, c.Query("confirm")); err != nil {
+		if errors.Is(err, service.ErrSlotDropDisabled) {
+			return withStatus(fiber.StatusForbidden, err)
+		}
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Replication slot dropped successfully",
+	})
+}
+
+// GetSchemaPlan handles GET /api/admin/schema/plan
+func (h *AdminHandler) GetSchemaPlan(c *fiber.Ctx) error {
+	plan, err := h.schemaService.Plan(c.Context(), "public.orders", h.orderIndex)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Schema plan fetched successfully",
+		"data":    plan,
+	})
+}
+
+// GetAutomationRules handles GET /api/admin/automation/rules
+func (h *AdminHandler) GetAutomationRules(c *fiber.Ctx) error {
+	rules := h.automationService.GetRules()
+
+	return c.JSON(fiber.Map{
+		"message": "Automation rules fetched successfully",
+		"data":    rules,
+		"count":   len(rules),
+	})
+}
+
+// RunAutomation handles POST /api/admin/automation/runs
+func (h *AdminHandler) RunAutomation(c *fiber.Ctx) error {
+	run, err := h.automationService.RunOnce(c.Context())
+	if err != nil {
+		return err
+	}
+	if run.Skipped {
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{
+			"message": "Automation is running on another replica",
+			"data":    run,
+		})
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Automation ran successfully",
+		"data":    run,
+	})
+}
@@ -0,0 +1,69 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// principalLocal is the key of the authenticated principal in the request locals
+const principalLocal = "principal"
+
+// AuthHandler authenticates the callers of the API
+type AuthHandler struct {
+	authService *service.AuthService
+}
+
+// NewAuthHandler creates a new AuthHandler
+func NewAuthHandler(authService *service.AuthService) *AuthHandler {
+	return &AuthHandler{
+		authService: authService,
+	}
+}
+
+// Authenticate resolves the principal of a request sent with a bearer token in the
+// Authorization header. Requests without the header continue anonymously; handlers that
+// need a principal reject them. Invalid tokens are answered with 401 Unauthorized.
+func (h *AuthHandler) Authenticate(c *fiber.Ctx) error {
+	authorization := c.Get(fiber.HeaderAuthorization)
+	if authorization == "" {
+		return c.Next()
+	}
+
+	scheme, token, _ := strings.Cut(authorization, " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return fmt.Errorf("%w: expected a bearer token", entity.ErrInvalidCredentials)
+	}
+	principal, err := h.authService.Authenticate(strings.TrimSpace(token))
+	if err != nil {
+		c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="invalid_token"`)
+		return err
+	}
+
+	c.Locals(principalLocal, principal)
+	return c.Next()
+}
+
+// RequireAdmin lets only admins through, after Authenticate. Anonymous requests are answered
+// with 401 Unauthorized and those of other principals with 403 Forbidden.
+func (h *AuthHandler) RequireAdmin(c *fiber.Ctx) error {
+	principal := requestPrincipal(c)
+	if principal == nil {
+		c.Set(fiber.HeaderWWWAuthenticate, "Bearer")
+		return fmt.Errorf("%w: admin routes need a bearer token", entity.ErrAuthenticationRequired)
+	}
+	if principal.Role != entity.PrincipalRole.Admin {
+		return fmt.Errorf("%w: %s", entity.ErrAdminRequired, c.Path())
+	}
+	return c.Next()
+}
+
+// requestPrincipal returns the authenticated principal of a request, or nil when it is anonymous
+func requestPrincipal(c *fiber.Ctx) *entity.Principal {
+	principal, _ := c.Locals(principalLocal).(*entity.Principal)
+	return principal
+}
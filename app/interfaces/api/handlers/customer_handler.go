@@ -0,0 +1,104 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// CustomerHandler handles HTTP requests for customers
+type CustomerHandler struct {
+	customerService *service.CustomerService
+}
+
+// NewCustomerHandler creates a new CustomerHandler
+func NewCustomerHandler(customerService *service.CustomerService) *CustomerHandler {
+	return &CustomerHandler{
+		customerService: customerService,
+	}
+}
+
+// GetAllCustomers handles GET /api/customers
+func (h *CustomerHandler) GetAllCustomers(c *fiber.Ctx) error {
+	customers, err := h.customerService.GetAllCustomers(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Customers fetched successfully",
+		"data":    customers,
+		"count":   len(customers),
+	})
+}
+
+// GetCustomer handles GET /api/customers/:id
+func (h *CustomerHandler) GetCustomer(c *fiber.Ctx) error {
+	id := c.Params("id")
+	customer, err := h.customerService.GetCustomerByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Customer fetched successfully",
+		"data":    customer,
+	})
+}
+
+// CreateCustomer handles POST /api/customers
+func (h *CustomerHandler) CreateCustomer(c *fiber.Ctx) error {
+	customer := new(entity.Customer)
+
+	// Parse request body
+	if err := c.BodyParser(customer); err != nil {
+		return badRequest(err)
+	}
+
+	// Create customer
+	if err := h.customerService.CreateCustomer(c.Context(), customer); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"message": "Customer created successfully",
+		"data":    customer,
+	})
+}
+
+// UpdateCustomer handles PUT /api/customers/:id
+func (h *CustomerHandler) UpdateCustomer(c *fiber.Ctx) error {
+	customer := new(entity.Customer)
+
+	// Parse request body
+	if err := c.BodyParser(customer); err != nil {
+		return badRequest(err)
+	}
+
+	// Set ID from path parameter
+	customer.ID = c.Params("id")
+
+	// Update customer
+	if err := h.customerService.UpdateCustomer(c.Context(), customer, requestActor(c)); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Customer updated successfully",
+		"data":    customer,
+	})
+}
+
+// DeleteCustomer handles DELETE /api/customers/:id
+func (h *CustomerHandler) DeleteCustomer(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	// Delete customer
+	if err := h.customerService.DeleteCustomer(c.Context(), id); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Customer deleted successfully",
+	})
+}
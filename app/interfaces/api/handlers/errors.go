@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/gofiber/fiber/v2/utils"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// problemContentType is the media type of RFC 7807 problem details
+const problemContentType = "application/problem+json"
+
+// problem is an RFC 7807 problem details object; errors lists invalid fields
+type problem struct {
+	Type     string              `json:"type"`
+	Title    string              `json:"title"`
+	Status   int                 `json:"status"`
+	Detail   string              `json:"detail,omitempty"`
+	Instance string              `json:"instance,omitempty"`
+	Errors   []entity.FieldError `json:"errors,omitempty"`
+}
+
+// statusError answers an error with a status other than the one its kind maps to
+type statusError struct {
+	status int
+	err    error
+}
+
+// withStatus makes the error handler answer err with status
+func withStatus(status int, err error) error {
+	return &statusError{status: status, err: err}
+}
+
+// Error returns the message of the wrapped error
+func (e *statusError) Error() string {
+	return e.err.Error()
+}
+
+// Unwrap returns the wrapped error
+func (e *statusError) Unwrap() error {
+	return e.err
+}
+
+// badRequest answers a request that could not be parsed
+func badRequest(err error) error {
+	return withStatus(fiber.StatusBadRequest, err)
+}
+
+// ErrorHandler is the Fiber error handler. It answers errors returned by handlers with
+// problem details, choosing the status from the kind of the error. Details of unexpected
+// errors are logged rather than returned.
+func ErrorHandler(c *fiber.Ctx, err error) error {
+	status := errorStatus(err)
+	p := problem{
+		Type:     "about:blank",
+		Title:    utils.StatusMessage(status),
+		Status:   status,
+		Detail:   err.Error(),
+		Instance: c.OriginalURL(),
+	}
+
+	var validation *entity.ValidationError
+	if errors.As(err, &validation) {
+		p.Errors = validation.Fields
+	}
+	if status == fiber.StatusInternalServerError {
+		log.Printf("%s %s failed: %v", c.Method(), c.OriginalURL(), err)
+		p.Detail = ""
+	}
+
+	return c.Status(status).JSON(p, problemContentType)
+}
+
+// errorStatus maps an error to its HTTP status by its kind
+func errorStatus(err error) int {
+	var withStatus *statusError
+	var fiberErr *fiber.Error
+	switch {
+	case errors.As(err, &withStatus):
+		return withStatus.status
+	case errors.As(err, &fiberErr):
+		return fiberErr.Code
+	case errors.Is(err, entity.ErrValidation):
+		return fiber.StatusBadRequest
+	case errors.Is(err, entity.ErrNotFound):
+		return fiber.StatusNotFound
+	case errors.Is(err, entity.ErrUnauthenticated):
+		return fiber.StatusUnauthorized
+	case errors.Is(err, entity.ErrForbidden):
+		return fiber.StatusForbidden
+	case errors.Is(err, entity.ErrConflict):
+		return fiber.StatusConflict
+	case errors.Is(err, entity.ErrUnavailable):
+		return fiber.StatusServiceUnavailable
+	case errors.Is(err, entity.ErrBulkRolledBack),
+		errors.Is(err, entity.ErrBulkSkipped):
+		return fiber.StatusFailedDependency
+	default:
+		return fiber.StatusInternalServerError
+	}
+}
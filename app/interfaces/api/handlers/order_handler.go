@@ -1,37 +1,157 @@
 package handlers
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
 	"github.com/gofiber/fiber/v2"
 	"github.com/mehmetymw/debezium-postgres-es/application/service"
 	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/patch"
 )
 
+const (
+	// actorHeader names the caller recorded in the order status history
+	actorHeader = "X-Actor"
+
+	// idempotencyKeyHeader carries the client's key for retrying a request safely
+	idempotencyKeyHeader = "Idempotency-Key"
+
+	// idempotentReplayedHeader marks responses replayed for a repeated idempotency key
+	idempotentReplayedHeader = "Idempotent-Replayed"
+
+	// maxIdempotencyKeyLength is the longest idempotency key accepted
+	maxIdempotencyKeyLength = 255
+)
+
+// updateOrderRequest is the body of PUT /api/orders/:id and the patched order of PATCH;
+// reason explains a status change
+type updateOrderRequest struct {
+	entity.Order
+	Reason string `json:"reason"`
+}
+
+// bulkOrderRequest is the body of POST /api/orders/_bulk
+type bulkOrderRequest struct {
+	Operations []entity.BulkOrderOperation `json:"operations"`
+}
+
 // OrderHandler handles HTTP requests for orders
 type OrderHandler struct {
-	orderService *service.OrderService
+	orderService       *service.OrderService
+	idempotencyService *service.IdempotencyService
+	// bulkAtomic runs bulk requests in one transaction instead of best-effort per operation
+	bulkAtomic        bool
+	maxBulkOperations int
 }
 
 // NewOrderHandler creates a new OrderHandler
-func NewOrderHandler(orderService *service.OrderService) *OrderHandler {
+func NewOrderHandler(orderService *service.OrderService, idempotencyService *service.IdempotencyService,
+	bulkAtomic bool, maxBulkOperations int) *OrderHandler {
 	return &OrderHandler{
-		orderService: orderService,
+		orderService:       orderService,
+		idempotencyService: idempotencyService,
+		bulkAtomic:         bulkAtomic,
+		maxBulkOperations:  maxBulkOperations,
 	}
 }
 
-// GetAllOrders handles GET /api/orders
+// Idempotent runs the next handler once per Idempotency-Key. Repeating a key with the same
+// method, path and body replays the stored response; reusing it for another request is
+// rejected. Server errors are not stored, so such requests can be retried with the same key.
+func (h *OrderHandler) Idempotent(c *fiber.Ctx) error {
+	key := c.Get(idempotencyKeyHeader)
+	if key == "" {
+		return c.Next()
+	}
+	if len(key) > maxIdempotencyKeyLength {
+		return entity.NewFieldError(entity.ErrValidation, idempotencyKeyHeader,
+			fmt.Sprintf("must be at most %d characters", maxIdempotencyKeyLength))
+	}
+
+	scope := idempotencyScope(c)
+	record, err := h.idempotencyService.Begin(c.Context(), scope, key, requestHash(c))
+	if errors.Is(err, entity.ErrIdempotencyKeyReused) {
+		return withStatus(fiber.StatusUnprocessableEntity, err)
+	}
+	if err != nil {
+		return err
+	}
+	if record != nil {
+		c.Set(idempotentReplayedHeader, "true")
+		c.Set(fiber.HeaderContentType, record.ContentType)
+		if record.ETag != "" {
+			c.Set(fiber.HeaderETag, record.ETag)
+		}
+		return c.Status(record.StatusCode).Send(record.Body)
+	}
+
+	// Errors are answered here rather than by the app, so their response can be stored
+	if err := c.Next(); err != nil {
+		if err := c.App().Config().ErrorHandler(c, err); err != nil {
+			if releaseErr := h.idempotencyService.Release(c.Context(), scope, key); releaseErr != nil {
+				log.Printf("Failed to release idempotency key %s: %v", key, releaseErr)
+			}
+			return err
+		}
+	}
+
+	res := c.Response()
+	if res.StatusCode() >= fiber.StatusInternalServerError {
+		if err := h.idempotencyService.Release(c.Context(), scope, key); err != nil {
+			log.Printf("Failed to release idempotency key %s: %v", key, err)
+		}
+		return nil
+	}
+	if err := h.idempotencyService.Complete(c.Context(), &entity.IdempotencyRecord{
+		Scope:       scope,
+		Key:         key,
+		StatusCode:  res.StatusCode(),
+		ContentType: string(res.Header.ContentType()),
+		ETag:        string(res.Header.Peek(fiber.HeaderETag)),
+		Body:        bytes.Clone(res.Body()),
+	}); err != nil {
+		log.Printf("Failed to store response for idempotency key %s: %v", key, err)
+	}
+	return nil
+}
+
+// GetAllOrders handles GET /api/orders, filtered and paged by the query parameters;
+// source=search reads from Elasticsearch instead of PostgreSQL
 func (h *OrderHandler) GetAllOrders(c *fiber.Ctx) error {
-	orders, err := h.orderService.GetAllOrders(c.Context())
+	query, err := parseOrderQuery(c)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": "Error fetching orders",
-			"error":   err.Error(),
-		})
+		return err
+	}
+
+	var page *entity.OrderPage
+	switch c.Query("source", "database") {
+	case "database":
+		page, err = h.orderService.GetOrders(c.Context(), query)
+	case "search":
+		page, err = h.orderService.SearchOrders(c.Context(), query)
+	default:
+		err = entity.NewFieldError(entity.ErrInvalidOrderQuery, "source", "must be database or search")
+	}
+	if err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
 		"message": "Orders fetched successfully",
-		"data":    orders,
-		"count":   len(orders),
+		"data":    page.Orders,
+		"count":   len(page.Orders),
+		"total":   page.Total,
+		"limit":   page.Limit,
+		"offset":  page.Offset,
 	})
 }
 
@@ -40,12 +160,10 @@ func (h *OrderHandler) GetOrder(c *fiber.Ctx) error {
 	id := c.Params("id")
 	order, err := h.orderService.GetOrderByID(c.Context(), id)
 	if err != nil {
-		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{
-			"message": "Order not found",
-			"error":   err.Error(),
-		})
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, versionETag(order.Version))
 	return c.JSON(fiber.Map{
 		"message": "Order fetched successfully",
 		"data":    order,
@@ -58,75 +176,176 @@ func (h *OrderHandler) CreateOrder(c *fiber.Ctx) error {
 
 	// Parse request body
 	if err := c.BodyParser(order); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": "Error parsing request",
-			"error":   err.Error(),
-		})
+		return badRequest(err)
 	}
 
 	// Create order
-	if err := h.orderService.CreateOrder(c.Context(), order); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": "Error creating order",
-			"error":   err.Error(),
-		})
+	if err := h.orderService.CreateOrder(c.Context(), order, requestActor(c)); err != nil {
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, versionETag(order.Version))
 	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
 		"message": "Order created successfully",
 		"data":    order,
 	})
 }
 
-// UpdateOrder handles PUT /api/orders/:id
+// UpdateOrder handles PUT /api/orders/:id, replacing the order with the request body
 func (h *OrderHandler) UpdateOrder(c *fiber.Ctx) error {
-	id := c.Params("id")
-	updateData := new(entity.Order)
+	updateData := new(updateOrderRequest)
 
 	// Parse request body
 	if err := c.BodyParser(updateData); err != nil {
-		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{
-			"message": "Error parsing request",
-			"error":   err.Error(),
-		})
+		return badRequest(err)
 	}
 
+	return h.replaceOrder(c, updateData)
+}
+
+// PatchOrder handles PATCH /api/orders/:id with a JSON Merge Patch or a JSON Patch body.
+// The patch applies to the order as returned by GET, where a reason member explains a status
+// change; the patched order then replaces the stored one.
+func (h *OrderHandler) PatchOrder(c *fiber.Ctx) error {
+	id := c.Params("id")
+
+	var apply func(doc, patch []byte) ([]byte, error)
+	switch mediaType := strings.TrimSpace(strings.Split(c.Get(fiber.HeaderContentType), ";")[0]); strings.ToLower(mediaType) {
+	case patch.MergePatchContentType:
+		apply = patch.MergePatch
+	case patch.JSONPatchContentType:
+		apply = patch.JSONPatch
+	default:
+		return fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("Content-Type must be %s or %s", patch.MergePatchContentType, patch.JSONPatchContentType))
+	}
+
+	order, err := h.orderService.GetOrderByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+	doc, err := json.Marshal(order)
+	if err != nil {
+		return err
+	}
+
+	patched, err := apply(doc, c.Body())
+	if errors.Is(err, patch.ErrTestFailed) {
+		return withStatus(fiber.StatusConflict, err)
+	}
+	if err != nil {
+		return badRequest(err)
+	}
+	updateData := new(updateOrderRequest)
+	if err := json.Unmarshal(patched, updateData); err != nil {
+		return badRequest(err)
+	}
+
+	// The patched version is the one read above unless the patch changed it
+	return h.replaceOrder(c, updateData)
+}
+
+// replaceOrder replaces the order named by the path with updateData and writes the response
+func (h *OrderHandler) replaceOrder(c *fiber.Ctx, updateData *updateOrderRequest) error {
+	id := c.Params("id")
+
 	// Set ID from path parameter
 	updateData.ID = id
 
-	// Update order
-	if err := h.orderService.UpdateOrder(c.Context(), updateData); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": "Error updating order",
-			"error":   err.Error(),
-		})
+	// The If-Match header takes precedence over the version in the body
+	ifMatch := c.Get(fiber.HeaderIfMatch)
+	if ifMatch != "" {
+		version, err := parseVersionETag(ifMatch)
+		if err != nil {
+			return badRequest(err)
+		}
+		updateData.Version = version
+	}
+
+	// Replace order
+	if err := h.orderService.ReplaceOrder(c.Context(), &updateData.Order, requestActor(c), updateData.Reason); err != nil {
+		if ifMatch != "" && errors.Is(err, entity.ErrOrderVersionConflict) {
+			return withStatus(fiber.StatusPreconditionFailed, err)
+		}
+		return err
 	}
 
 	// Get updated order
 	updatedOrder, err := h.orderService.GetOrderByID(c.Context(), id)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": "Error fetching updated order",
-			"error":   err.Error(),
-		})
+		return err
 	}
 
+	c.Set(fiber.HeaderETag, versionETag(updatedOrder.Version))
 	return c.JSON(fiber.Map{
 		"message": "Order updated successfully",
 		"data":    updatedOrder,
 	})
 }
 
+// BulkOrders handles POST /api/orders/_bulk, answering 207 Multi-Status when an operation failed
+func (h *OrderHandler) BulkOrders(c *fiber.Ctx) error {
+	request := new(bulkOrderRequest)
+	if err := c.BodyParser(request); err != nil {
+		return badRequest(err)
+	}
+	if len(request.Operations) == 0 || len(request.Operations) > h.maxBulkOperations {
+		return entity.NewFieldError(entity.ErrInvalidBulkOperation, "operations",
+			fmt.Sprintf("must hold between 1 and %d operations", h.maxBulkOperations))
+	}
+
+	results := h.orderService.BulkOrders(c.Context(), request.Operations, requestActor(c), h.bulkAtomic)
+
+	items := make([]fiber.Map, len(results))
+	failed := 0
+	for i, result := range results {
+		item := fiber.Map{"index": i, "op": result.Op}
+		if result.ID != "" {
+			item["id"] = result.ID
+		}
+		switch {
+		case result.Err != nil:
+			failed++
+			item["status"] = errorStatus(result.Err)
+			item["error"] = result.Err.Error()
+			var validation *entity.ValidationError
+			if errors.As(result.Err, &validation) {
+				item["errors"] = validation.Fields
+			}
+		case result.Op == entity.BulkOrderOp.Create:
+			item["status"] = fiber.StatusCreated
+		default:
+			item["status"] = fiber.StatusOK
+		}
+		if result.Order != nil {
+			item["id"] = result.Order.ID
+			item["data"] = result.Order
+		}
+		items[i] = item
+	}
+
+	status := fiber.StatusOK
+	message := "Bulk operations applied successfully"
+	if failed > 0 {
+		status = fiber.StatusMultiStatus
+		message = "Some bulk operations failed"
+	}
+	return c.Status(status).JSON(fiber.Map{
+		"message": message,
+		"data":    items,
+		"count":   len(items),
+		"failed":  failed,
+		"atomic":  h.bulkAtomic,
+	})
+}
+
 // DeleteOrder handles DELETE /api/orders/:id
 func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
 	id := c.Params("id")
 
 	// Delete order
-	if err := h.orderService.DeleteOrder(c.Context(), id); err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": "Error deleting order",
-			"error":   err.Error(),
-		})
+	if err := h.orderService.DeleteOrder(c.Context(), id, requestActor(c)); err != nil {
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -134,15 +353,53 @@ func (h *OrderHandler) DeleteOrder(c *fiber.Ctx) error {
 	})
 }
 
+// GetDeletedOrders handles GET /api/orders/deleted
+func (h *OrderHandler) GetDeletedOrders(c *fiber.Ctx) error {
+	orders, err := h.orderService.GetDeletedOrders(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Deleted orders fetched successfully",
+		"data":    orders,
+		"count":   len(orders),
+	})
+}
+
+// RestoreOrder handles POST /api/orders/:id/restore
+func (h *OrderHandler) RestoreOrder(c *fiber.Ctx) error {
+	id := c.Params("id")
+	order, err := h.orderService.RestoreOrder(c.Context(), id, requestActor(c))
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderETag, versionETag(order.Version))
+	return c.JSON(fiber.Map{
+		"message": "Order restored successfully",
+		"data":    order,
+	})
+}
+
+// PurgeOrder handles DELETE /api/orders/:id/purge
+func (h *OrderHandler) PurgeOrder(c *fiber.Ctx) error {
+	id := c.Params("id")
+	if err := h.orderService.PurgeOrder(c.Context(), id, requestActor(c)); err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Order purged successfully",
+	})
+}
+
 // GetOrdersByStatus handles GET /api/orders/status/:status
 func (h *OrderHandler) GetOrdersByStatus(c *fiber.Ctx) error {
 	status := c.Params("status")
 	orders, err := h.orderService.GetOrdersByStatus(c.Context(), status)
 	if err != nil {
-		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{
-			"message": "Error fetching orders",
-			"error":   err.Error(),
-		})
+		return err
 	}
 
 	return c.JSON(fiber.Map{
@@ -151,3 +408,143 @@ func (h *OrderHandler) GetOrdersByStatus(c *fiber.Ctx) error {
 		"count":   len(orders),
 	})
 }
+
+// GetOrderTransitions handles GET /api/orders/:id/transitions
+func (h *OrderHandler) GetOrderTransitions(c *fiber.Ctx) error {
+	id := c.Params("id")
+	order, transitions, err := h.orderService.GetOrderTransitions(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Order transitions fetched successfully",
+		"data": fiber.Map{
+			"status":      order.Status,
+			"transitions": transitions,
+		},
+	})
+}
+
+// GetOrderHistory handles GET /api/orders/:id/history
+func (h *OrderHandler) GetOrderHistory(c *fiber.Ctx) error {
+	id := c.Params("id")
+	history, err := h.orderService.GetOrderHistory(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Order history fetched successfully",
+		"data":    history,
+		"count":   len(history),
+	})
+}
+
+// GetOrderFlags handles GET /api/orders/:id/flags
+func (h *OrderHandler) GetOrderFlags(c *fiber.Ctx) error {
+	id := c.Params("id")
+	flags, err := h.orderService.GetOrderFlags(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Order flags fetched successfully",
+		"data":    flags,
+		"count":   len(flags),
+	})
+}
+
+// parseOrderQuery reads an order query from the status, customerId, createdFrom, createdTo,
+// updatedFrom, updatedTo, sort, limit and offset parameters. status takes a comma-separated
+// list, times are RFC 3339 and a sort field prefixed with "-" sorts in descending order.
+func parseOrderQuery(c *fiber.Ctx) (entity.OrderQuery, error) {
+	query := entity.OrderQuery{CustomerID: c.Query("customerId")}
+	if statuses := c.Query("status"); statuses != "" {
+		query.Statuses = strings.Split(statuses, ",")
+	}
+
+	for param, target := range map[string]**time.Time{
+		"createdFrom": &query.CreatedFrom,
+		"createdTo":   &query.CreatedTo,
+		"updatedFrom": &query.UpdatedFrom,
+		"updatedTo":   &query.UpdatedTo,
+	} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		t, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return query, entity.NewFieldError(entity.ErrInvalidOrderQuery, param, fmt.Sprintf("must be an RFC 3339 time, got %s", value))
+		}
+		*target = &t
+	}
+
+	sort := c.Query("sort")
+	query.Descending = strings.HasPrefix(sort, "-")
+	query.Sort = strings.TrimPrefix(sort, "-")
+
+	for param, target := range map[string]*int{"limit": &query.Limit, "offset": &query.Offset} {
+		value := c.Query(param)
+		if value == "" {
+			continue
+		}
+		n, err := strconv.Atoi(value)
+		if err != nil {
+			return query, entity.NewFieldError(entity.ErrInvalidOrderQuery, param, fmt.Sprintf("must be an integer, got %s", value))
+		}
+		*target = n
+	}
+
+	return query, nil
+}
+
+// requestHash identifies a request by its method, path, preconditions and body
+func requestHash(c *fiber.Ctx) string {
+	hash := sha256.New()
+	for _, part := range []string{c.Method(), c.Path(), c.Get(fiber.HeaderContentType), c.Get(fiber.HeaderIfMatch)} {
+		hash.Write([]byte(part))
+		hash.Write([]byte{0})
+	}
+	hash.Write(c.Body())
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// versionETag renders an order version as a strong entity tag
+func versionETag(version int64) string {
+	return strconv.Quote(strconv.FormatInt(version, 10))
+}
+
+// parseVersionETag reads an order version from an If-Match header, accepting weak tags
+func parseVersionETag(header string) (int64, error) {
+	tag := strings.TrimPrefix(strings.TrimSpace(header), "W/")
+	version, err := strconv.ParseInt(strings.Trim(tag, `"`), 10, 64)
+	if err != nil || version <= 0 {
+		return 0, fmt.Errorf("If-Match must be an order version, got %s", header)
+	}
+	return version, nil
+}
+
+// requestActor returns the authenticated principal, or else the caller named in the actor
+// header, or "api" when it is absent
+func requestActor(c *fiber.Ctx) string {
+	if principal := requestPrincipal(c); principal != nil {
+		return principal.Scope()
+	}
+	if actor := c.Get(actorHeader); actor != "" {
+		return actor
+	}
+	return "api"
+}
+
+// idempotencyScope returns the scope of the idempotency keys of a request: its authenticated
+// principal, or the scope shared by all anonymous callers. Unlike the actor header it cannot
+// be chosen by the caller, so a key never replays another principal's response.
+func idempotencyScope(c *fiber.Ctx) string {
+	if principal := requestPrincipal(c); principal != nil {
+		return principal.Scope()
+	}
+	return "anonymous"
+}
@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// streamRetry is how long clients wait before reconnecting to a closed order stream
+const streamRetry = 3 * time.Second
+
+// streamEventNames maps order event types to the event names of the order stream
+var streamEventNames = map[string]string{
+	entity.OrderEventType.Created:       "created",
+	entity.OrderEventType.Updated:       "updated",
+	entity.OrderEventType.StatusChanged: "updated",
+	entity.OrderEventType.Restored:      "updated",
+	entity.OrderEventType.Flagged:       "updated",
+	entity.OrderEventType.Deleted:       "deleted",
+	entity.OrderEventType.Purged:        "deleted",
+}
+
+// OrderStreamHandler streams order changes to clients as Server-Sent Events
+type OrderStreamHandler struct {
+	feed *event.Feed
+	// buffer is how many events a client may fall behind before its stream is ended
+	buffer int
+	// heartbeat is how often an idle stream sends a comment to keep proxies from closing it
+	heartbeat time.Duration
+}
+
+// NewOrderStreamHandler creates a new OrderStreamHandler
+func NewOrderStreamHandler(feed *event.Feed, buffer int, heartbeat time.Duration) *OrderStreamHandler {
+	return &OrderStreamHandler{
+		feed:      feed,
+		buffer:    buffer,
+		heartbeat: heartbeat,
+	}
+}
+
+// StreamOrders handles GET /api/orders/stream. Clients that reconnect with the Last-Event-ID
+// header, to this or another replica, receive the changes they missed; when those are no
+// longer in the outbox or too many, the stream starts with a reset event and the client
+// should refetch the orders it shows.
+func (h *OrderStreamHandler) StreamOrders(c *fiber.Ctx) error {
+	var statuses []string
+	if value := c.Query("status"); value != "" {
+		statuses = strings.Split(value, ",")
+		for _, status := range statuses {
+			if !entity.IsValidOrderStatus(status) {
+				return entity.NewFieldError(entity.ErrInvalidOrderStatus, "status", fmt.Sprintf("%q is not an order status", status))
+			}
+		}
+	}
+	customerID := c.Query("customerId")
+
+	sub, missed, resumed, err := h.feed.Subscribe(c.Context(), c.Get("Last-Event-ID"), h.buffer, func(event *entity.OrderEvent) bool {
+		if customerID != "" && event.Order.CustomerID != customerID {
+			return false
+		}
+		// A status change out of the filtered statuses is sent too, so clients can drop the order
+		return len(statuses) == 0 || slices.Contains(statuses, event.Order.Status) ||
+			slices.Contains(statuses, event.FromStatus)
+	})
+	if err != nil {
+		return err
+	}
+
+	c.Set(fiber.HeaderContentType, "text/event-stream")
+	c.Set(fiber.HeaderCacheControl, "no-cache")
+	c.Set(fiber.HeaderConnection, "keep-alive")
+	c.Set("X-Accel-Buffering", "no")
+
+	c.Context().SetBodyStreamWriter(func(w *bufio.Writer) {
+		defer h.feed.Unsubscribe(sub)
+
+		fmt.Fprintf(w, "retry: %d\n\n", streamRetry.Milliseconds())
+		if !resumed {
+			fmt.Fprint(w, "event: reset\ndata: {}\n\n")
+		}
+		for _, held := range missed {
+			writeStreamEvent(w, held)
+		}
+		if w.Flush() != nil {
+			return
+		}
+
+		ticker := time.NewTicker(h.heartbeat)
+		defer ticker.Stop()
+		for {
+			select {
+			case held := <-sub.Events():
+				writeStreamEvent(w, held)
+			case <-ticker.C:
+				fmt.Fprint(w, ": heartbeat\n\n")
+			case <-sub.Done():
+				// The client fell too far behind; it reconnects and resumes or resets
+				return
+			}
+			if w.Flush() != nil {
+				return
+			}
+		}
+	})
+	return nil
+}
+
+// writeStreamEvent writes an order event in the event stream format
+func writeStreamEvent(w *bufio.Writer, held event.FeedEvent) {
+	data, err := json.Marshal(held.Event)
+	if err != nil {
+		return
+	}
+	fmt.Fprintf(w, "id: %s\nevent: %s\ndata: %s\n\n", held.ID, streamEventNames[held.Event.Type], data)
+}
@@ -0,0 +1,278 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sync"
+	"time"
+
+	"github.com/gofiber/contrib/websocket"
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// trackingTimeout bounds the writes to a tracking connection and the lookups of its subscriptions
+const trackingTimeout = 10 * time.Second
+
+// trackingRequest is a message sent by a tracking client
+type trackingRequest struct {
+	Type    string `json:"type"`
+	OrderID string `json:"orderId"`
+	Token   string `json:"token"`
+}
+
+// trackingMessage is a message sent to a tracking client. Change messages carry the order
+// after the change and the fields that changed since the previous message about it.
+type trackingMessage struct {
+	Type          string        `json:"type"`
+	OrderID       string        `json:"orderId,omitempty"`
+	Event         string        `json:"event,omitempty"`
+	ChangedFields []string      `json:"changedFields,omitempty"`
+	Order         *entity.Order `json:"order,omitempty"`
+	OccurredAt    *time.Time    `json:"occurredAt,omitempty"`
+	Code          string        `json:"code,omitempty"`
+	Message       string        `json:"message,omitempty"`
+}
+
+// OrderTrackingHandler pushes the changes of individual orders to WebSocket clients, such as
+// customer order tracking pages. Every subscription is authorized with the order's tracking token.
+type OrderTrackingHandler struct {
+	trackingService *service.TrackingService
+	feed            *event.Feed
+	// buffer is how many events a connection may fall behind before it is closed
+	buffer int
+	// heartbeat is how often connections are pinged; one silent for two heartbeats is closed
+	heartbeat time.Duration
+	// maxSubscriptions is how many orders one connection may track
+	maxSubscriptions int
+}
+
+// NewOrderTrackingHandler creates a new OrderTrackingHandler
+func NewOrderTrackingHandler(trackingService *service.TrackingService, feed *event.Feed, buffer int, heartbeat time.Duration, maxSubscriptions int) *OrderTrackingHandler {
+	return &OrderTrackingHandler{
+		trackingService:  trackingService,
+		feed:             feed,
+		buffer:           buffer,
+		heartbeat:        heartbeat,
+		maxSubscriptions: maxSubscriptions,
+	}
+}
+
+// GetTrackingToken handles GET /api/orders/:id/tracking-token. Tokens are only issued to an
+// authenticated principal that may access the order.
+func (h *OrderTrackingHandler) GetTrackingToken(c *fiber.Ctx) error {
+	id := c.Params("id")
+	token, expiresAt, err := h.trackingService.IssueToken(c.Context(), requestPrincipal(c), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Tracking token issued successfully",
+		"data": fiber.Map{
+			"orderId":   id,
+			"token":     token,
+			"expiresAt": expiresAt,
+		},
+	})
+}
+
+// TrackOrders handles GET /api/orders/track, upgrading it to a WebSocket connection
+func (h *OrderTrackingHandler) TrackOrders() fiber.Handler {
+	return websocket.New(func(conn *websocket.Conn) {
+		session := &trackingSession{handler: h, conn: conn, orders: make(map[string]entity.Order)}
+		session.run()
+	})
+}
+
+// trackingSession is one tracking connection. Requests, events and pings are handled in turn
+// by run, the only writer of the connection; the orders map is also read by the feed filter.
+type trackingSession struct {
+	handler *OrderTrackingHandler
+	conn    *websocket.Conn
+	mu      sync.Mutex
+	// orders holds the last state sent of every tracked order
+	orders map[string]entity.Order
+}
+
+// run serves the connection until the client goes away, stops answering pings or falls behind
+func (s *trackingSession) run() {
+	h := s.handler
+	sub, _, _, _ := h.feed.Subscribe(context.Background(), "", h.buffer, func(event *entity.OrderEvent) bool {
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		_, tracked := s.orders[event.OrderID]
+		return tracked
+	})
+	defer h.feed.Unsubscribe(sub)
+
+	s.conn.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+	s.conn.SetPongHandler(func(string) error {
+		return s.conn.SetReadDeadline(time.Now().Add(2 * h.heartbeat))
+	})
+	requests := make(chan trackingRequest)
+	done := make(chan struct{})
+	defer close(done)
+	go s.read(requests, done)
+
+	ticker := time.NewTicker(h.heartbeat)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case request, ok := <-requests:
+			if !ok {
+				return
+			}
+			err = s.handle(request)
+		case held := <-sub.Events():
+			err = s.deliver(&held.Event)
+		case <-ticker.C:
+			err = s.conn.WriteControl(websocket.PingMessage, nil, time.Now().Add(trackingTimeout))
+		case <-sub.Done():
+			// The client fell too far behind; it reconnects and subscribes again
+			s.conn.WriteControl(websocket.CloseMessage,
+				websocket.FormatCloseMessage(websocket.CloseTryAgainLater, "too far behind"),
+				time.Now().Add(trackingTimeout))
+			return
+		}
+		if err != nil {
+			return
+		}
+	}
+}
+
+// read passes the client's requests to run until the connection fails or run is done. Requests
+// that are not valid JSON are passed on without a type, to be answered with an error.
+func (s *trackingSession) read(requests chan<- trackingRequest, done <-chan struct{}) {
+	defer close(requests)
+	for {
+		var request trackingRequest
+		if err := s.conn.ReadJSON(&request); err != nil {
+			var syntaxErr *json.SyntaxError
+			var typeErr *json.UnmarshalTypeError
+			if !errors.As(err, &syntaxErr) && !errors.As(err, &typeErr) {
+				return
+			}
+		}
+		select {
+		case requests <- request:
+		case <-done:
+			return
+		}
+	}
+}
+
+// handle answers a subscribe or unsubscribe request
+func (s *trackingSession) handle(request trackingRequest) error {
+	switch request.Type {
+	case "subscribe":
+		return s.subscribe(request)
+	case "unsubscribe":
+		s.mu.Lock()
+		delete(s.orders, request.OrderID)
+		s.mu.Unlock()
+		return s.send(trackingMessage{Type: "unsubscribed", OrderID: request.OrderID})
+	default:
+		return s.send(trackingMessage{Type: "error", OrderID: request.OrderID, Code: "BAD_REQUEST",
+			Message: "expected a JSON object with type subscribe or unsubscribe"})
+	}
+}
+
+// subscribe authorizes a subscription and answers it with the current state of the order.
+// The order is tracked before it is read, so queued events older than that state are skipped
+// by deliver rather than missed.
+func (s *trackingSession) subscribe(request trackingRequest) error {
+	s.mu.Lock()
+	_, tracked := s.orders[request.OrderID]
+	full := !tracked && len(s.orders) >= s.handler.maxSubscriptions
+	if !tracked && !full {
+		s.orders[request.OrderID] = entity.Order{}
+	}
+	s.mu.Unlock()
+	if full {
+		return s.send(trackingMessage{Type: "error", OrderID: request.OrderID, Code: "TOO_MANY_SUBSCRIPTIONS",
+			Message: "too many orders tracked on this connection"})
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), trackingTimeout)
+	defer cancel()
+	order, err := s.handler.trackingService.Authorize(ctx, request.OrderID, request.Token)
+	if err != nil {
+		if !tracked {
+			s.mu.Lock()
+			delete(s.orders, request.OrderID)
+			s.mu.Unlock()
+		}
+		return s.send(trackingError(request.OrderID, err))
+	}
+
+	s.mu.Lock()
+	s.orders[order.ID] = *order
+	s.mu.Unlock()
+	return s.send(trackingMessage{Type: "subscribed", OrderID: order.ID, Order: order})
+}
+
+// deliver sends an event about a tracked order with the fields it changed. Events the client
+// has already seen the effect of are skipped; a purged order is no longer tracked.
+func (s *trackingSession) deliver(event *entity.OrderEvent) error {
+	s.mu.Lock()
+	before, tracked := s.orders[event.OrderID]
+	s.mu.Unlock()
+	if !tracked || event.Order.Version < before.Version {
+		return nil
+	}
+	changed := entity.ChangedOrderFields(&before, &event.Order)
+	if len(changed) == 0 && event.Type != entity.OrderEventType.Flagged && event.Type != entity.OrderEventType.Purged {
+		return nil
+	}
+
+	s.mu.Lock()
+	if event.Type == entity.OrderEventType.Purged {
+		delete(s.orders, event.OrderID)
+	} else {
+		s.orders[event.OrderID] = event.Order
+	}
+	s.mu.Unlock()
+
+	occurredAt := event.OccurredAt
+	return s.send(trackingMessage{
+		Type:          "change",
+		OrderID:       event.OrderID,
+		Event:         event.Type,
+		ChangedFields: changed,
+		Order:         &event.Order,
+		OccurredAt:    &occurredAt,
+	})
+}
+
+// send writes a message to the client
+func (s *trackingSession) send(message trackingMessage) error {
+	s.conn.SetWriteDeadline(time.Now().Add(trackingTimeout))
+	return s.conn.WriteJSON(message)
+}
+
+// trackingError describes a failed subscription with the code its kind maps to, the
+// counterpart of the REST error handler. Details of unexpected errors are logged rather than sent.
+func trackingError(orderID string, err error) trackingMessage {
+	message := trackingMessage{Type: "error", OrderID: orderID, Message: err.Error()}
+	switch {
+	case errors.Is(err, entity.ErrUnauthenticated):
+		message.Code = "UNAUTHENTICATED"
+	case errors.Is(err, entity.ErrForbidden):
+		message.Code = "FORBIDDEN"
+	case errors.Is(err, entity.ErrNotFound):
+		message.Code = "NOT_FOUND"
+	case errors.Is(err, entity.ErrUnavailable):
+		message.Code = "UNAVAILABLE"
+	default:
+		log.Printf("Tracking subscription to order %s failed: %v", orderID, err)
+		message.Code = "INTERNAL"
+		message.Message = "internal error"
+	}
+	return message
+}
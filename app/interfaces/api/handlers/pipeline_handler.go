@@ -0,0 +1,67 @@
+package handlers
+
+import (
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// PipelineHandler handles HTTP requests for CDC pipeline operations
+type PipelineHandler struct {
+	snapshotService *service.SnapshotService
+}
+
+// NewPipelineHandler creates a new PipelineHandler
+func NewPipelineHandler(snapshotService *service.SnapshotService) *PipelineHandler {
+	return &PipelineHandler{
+		snapshotService: snapshotService,
+	}
+}
+
+// RequestSnapshot handles POST /api/pipeline/snapshots
+func (h *PipelineHandler) RequestSnapshot(c *fiber.Ctx) error {
+	request := new(entity.SnapshotRequest)
+
+	// Parse request body
+	if err := c.BodyParser(request); err != nil {
+		return badRequest(err)
+	}
+
+	// Send snapshot signal
+	if err := h.snapshotService.RequestSnapshot(c.Context(), request); err != nil {
+		return err
+	}
+
+	return c.Status(fiber.StatusAccepted).JSON(fiber.Map{
+		"message": "Snapshot requested successfully",
+		"data":    request,
+	})
+}
+
+// GetAllSnapshots handles GET /api/pipeline/snapshots
+func (h *PipelineHandler) GetAllSnapshots(c *fiber.Ctx) error {
+	requests, err := h.snapshotService.GetAllSnapshots(c.Context())
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Snapshots fetched successfully",
+		"data":    requests,
+		"count":   len(requests),
+	})
+}
+
+// GetSnapshot handles GET /api/pipeline/snapshots/:id
+func (h *PipelineHandler) GetSnapshot(c *fiber.Ctx) error {
+	id := c.Params("id")
+	request, err := h.snapshotService.GetSnapshotByID(c.Context(), id)
+	if err != nil {
+		return err
+	}
+
+	return c.JSON(fiber.Map{
+		"message": "Snapshot fetched successfully",
+		"data":    request,
+	})
+}
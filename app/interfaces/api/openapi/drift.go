@@ -0,0 +1,57 @@
+package openapi
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// Drift compares the registered routes with the document and describes every route that is
+// served but not documented, and every operation that is documented but not served. HEAD
+// routes added by the router for GET routes are ignored.
+func (s *Spec) Drift(routes []fiber.Route) []string {
+	served := make(map[string]bool)
+	for _, route := range routes {
+		if route.Method == fiber.MethodHead || route.Method == fiber.MethodConnect {
+			continue
+		}
+		served[route.Method+" "+templatePath(route.Path)] = true
+	}
+
+	documented := make(map[string]bool)
+	for _, p := range s.paths {
+		for method := range p.operations {
+			documented[method+" "+p.template] = true
+		}
+	}
+
+	var drift []string
+	for route := range served {
+		if !documented[route] {
+			drift = append(drift, fmt.Sprintf("%s is served but not documented", route))
+		}
+	}
+	for route := range documented {
+		if !served[route] {
+			drift = append(drift, fmt.Sprintf("%s is documented but not served", route))
+		}
+	}
+	sort.Strings(drift)
+	return drift
+}
+
+// templatePath rewrites a Fiber route path like /api/orders/:id/ to /api/orders/{id}
+func templatePath(routePath string) string {
+	if routePath != "/" {
+		routePath = strings.TrimSuffix(routePath, "/")
+	}
+	segments := strings.Split(routePath, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") {
+			segments[i] = "{" + strings.TrimSuffix(strings.TrimPrefix(segment, ":"), "?") + "}"
+		}
+	}
+	return strings.Join(segments, "/")
+}
@@ -0,0 +1,66 @@
+package openapi_test
+
+import (
+	"slices"
+	"testing"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/handlers"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/openapi"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/routes"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/graphql"
+)
+
+// newApp registers the routes of the server the way main does. The services have no
+// repositories, since no request is served.
+func newApp(t *testing.T) (*fiber.App, *openapi.Spec) {
+	t.Helper()
+	spec, err := openapi.Load()
+	if err != nil {
+		t.Fatalf("openapi.Load() error = %v", err)
+	}
+
+	dispatcher := event.NewDispatcher()
+	feed := event.NewFeed(nil, dispatcher, 10)
+	orderService := service.NewOrderService(nil, nil, nil, nil, dispatcher, false)
+	customerService := service.NewCustomerService(nil, nil, nil, dispatcher)
+	graphqlHandler, err := graphql.NewHandler(orderService, customerService, 10)
+	if err != nil {
+		t.Fatalf("graphql.NewHandler() error = %v", err)
+	}
+
+	app := fiber.New(fiber.Config{ErrorHandler: handlers.ErrorHandler})
+	routes.SetupRoutes(app,
+		handlers.NewAuthHandler(service.NewAuthService(nil)),
+		handlers.NewOrderHandler(orderService, service.NewIdempotencyService(nil, 0, 0), true, 10),
+		handlers.NewOrderStreamHandler(feed, 10, 0),
+		handlers.NewOrderTrackingHandler(service.NewTrackingService(nil, nil, 0), feed, 10, 0, 10),
+		handlers.NewCustomerHandler(customerService),
+		handlers.NewPipelineHandler(nil),
+		handlers.NewAdminHandler(nil, nil, nil, nil, nil, ""),
+		graphqlHandler,
+		spec)
+	return app, spec
+}
+
+func TestRoutesMatchSpecification(t *testing.T) {
+	app, spec := newApp(t)
+	if drift := spec.Drift(app.GetRoutes(true)); len(drift) > 0 {
+		for _, route := range drift {
+			t.Error(route)
+		}
+	}
+}
+
+func TestDriftReportsUndocumentedRoutes(t *testing.T) {
+	app, spec := newApp(t)
+	app.Get("/api/orders/:id/undocumented", func(c *fiber.Ctx) error { return nil })
+
+	drift := spec.Drift(app.GetRoutes(true))
+	want := "GET /api/orders/{id}/undocumented is served but not documented"
+	if !slices.Contains(drift, want) {
+		t.Errorf("Drift() = %v, want it to contain %q", drift, want)
+	}
+}
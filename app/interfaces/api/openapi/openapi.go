@@ -0,0 +1,187 @@
+package openapi
+
+import (
+	_ "embed"
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// document is the OpenAPI description of the HTTP API. It is maintained by hand next to the
+// routes; Drift reports where the two disagree.
+//
+//go:embed openapi.json
+var document []byte
+
+// docsPage renders the document with Swagger UI
+const docsPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>%s</title>
+  <link rel="stylesheet" href="https://unpkg.com/swagger-ui-dist@5/swagger-ui.css">
+</head>
+<body>
+  <div id="swagger-ui"></div>
+  <script src="https://unpkg.com/swagger-ui-dist@5/swagger-ui-bundle.js"></script>
+  <script>
+    window.ui = SwaggerUIBundle({ url: "/openapi.json", dom_id: "#swagger-ui" });
+  </script>
+</body>
+</html>
+`
+
+// methods are the operation keys of a path item
+var methods = []string{"get", "put", "post", "delete", "options", "head", "patch", "trace"}
+
+// Spec is the parsed OpenAPI document
+type Spec struct {
+	raw        []byte
+	title      string
+	paths      []*path
+	schemas    map[string]*schema
+	parameters map[string]*parameter
+}
+
+// path is a templated path with its operations by upper-case method
+type path struct {
+	template   string
+	segments   []string
+	literals   int
+	operations map[string]*operation
+}
+
+// operation is the part of an operation used to validate requests
+type operation struct {
+	Parameters  []*parameter `json:"parameters"`
+	RequestBody *struct {
+		Required bool `json:"required"`
+		Content  map[string]struct {
+			Schema *schema `json:"schema"`
+		} `json:"content"`
+	} `json:"requestBody"`
+}
+
+// parameter is a path, query or header parameter
+type parameter struct {
+	Ref      string  `json:"$ref"`
+	Name     string  `json:"name"`
+	In       string  `json:"in"`
+	Required bool    `json:"required"`
+	Schema   *schema `json:"schema"`
+}
+
+// Load parses the embedded OpenAPI document
+func Load() (*Spec, error) {
+	var doc struct {
+		Info struct {
+			Title string `json:"title"`
+		} `json:"info"`
+		Paths      map[string]map[string]json.RawMessage `json:"paths"`
+		Components struct {
+			Schemas    map[string]*schema    `json:"schemas"`
+			Parameters map[string]*parameter `json:"parameters"`
+		} `json:"components"`
+	}
+	if err := json.Unmarshal(document, &doc); err != nil {
+		return nil, fmt.Errorf("invalid OpenAPI document: %w", err)
+	}
+
+	spec := &Spec{
+		raw:        document,
+		title:      doc.Info.Title,
+		schemas:    doc.Components.Schemas,
+		parameters: doc.Components.Parameters,
+	}
+	for template, item := range doc.Paths {
+		p := &path{
+			template:   template,
+			segments:   strings.Split(strings.Trim(template, "/"), "/"),
+			operations: make(map[string]*operation),
+		}
+		for _, segment := range p.segments {
+			if !isTemplate(segment) {
+				p.literals++
+			}
+		}
+		for _, method := range methods {
+			raw, ok := item[method]
+			if !ok {
+				continue
+			}
+			var op operation
+			if err := json.Unmarshal(raw, &op); err != nil {
+				return nil, fmt.Errorf("invalid OpenAPI operation %s %s: %w", strings.ToUpper(method), template, err)
+			}
+			for i, param := range op.Parameters {
+				if param.Ref == "" {
+					continue
+				}
+				resolved, ok := spec.parameters[strings.TrimPrefix(param.Ref, "#/components/parameters/")]
+				if !ok {
+					return nil, fmt.Errorf("unknown OpenAPI parameter %s in %s %s", param.Ref, strings.ToUpper(method), template)
+				}
+				op.Parameters[i] = resolved
+			}
+			p.operations[strings.ToUpper(method)] = &op
+		}
+		spec.paths = append(spec.paths, p)
+	}
+
+	// Prefer literal segments, so /api/orders/deleted wins over /api/orders/{id}
+	sort.Slice(spec.paths, func(i, j int) bool {
+		if spec.paths[i].literals != spec.paths[j].literals {
+			return spec.paths[i].literals > spec.paths[j].literals
+		}
+		return spec.paths[i].template < spec.paths[j].template
+	})
+	return spec, nil
+}
+
+// Document serves the OpenAPI document
+func (s *Spec) Document(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMEApplicationJSON)
+	return c.Send(s.raw)
+}
+
+// Docs serves a page rendering the OpenAPI document
+func (s *Spec) Docs(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(fmt.Sprintf(docsPage, s.title))
+}
+
+// match finds the operation for a request and the values of its path parameters
+func (s *Spec) match(method, requestPath string) (*operation, map[string]string) {
+	segments := strings.Split(strings.Trim(requestPath, "/"), "/")
+	for _, p := range s.paths {
+		op, ok := p.operations[method]
+		if !ok || len(p.segments) != len(segments) {
+			continue
+		}
+		values := make(map[string]string)
+		matched := true
+		for i, segment := range p.segments {
+			switch {
+			case isTemplate(segment):
+				values[strings.Trim(segment, "{}")] = segments[i]
+			case segment != segments[i]:
+				matched = false
+			}
+			if !matched {
+				break
+			}
+		}
+		if matched {
+			return op, values
+		}
+	}
+	return nil, nil
+}
+
+// isTemplate reports whether a path segment is a parameter
+func isTemplate(segment string) bool {
+	return strings.HasPrefix(segment, "{") && strings.HasSuffix(segment, "}")
+}
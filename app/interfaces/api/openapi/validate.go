@@ -0,0 +1,304 @@
+package openapi
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/mail"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// ErrInvalidRequest is returned when a request does not match the OpenAPI document
+var ErrInvalidRequest = entity.NewError(entity.ErrValidation, "request does not match the API specification")
+
+// schema is the subset of an OpenAPI schema object used to validate requests
+type schema struct {
+	Ref        string             `json:"$ref"`
+	Type       string             `json:"type"`
+	Format     string             `json:"format"`
+	Enum       []any              `json:"enum"`
+	Required   []string           `json:"required"`
+	Properties map[string]*schema `json:"properties"`
+	Items      *schema            `json:"items"`
+	AllOf      []*schema          `json:"allOf"`
+	Minimum    *float64           `json:"minimum"`
+	Maximum    *float64           `json:"maximum"`
+	MinLength  *int               `json:"minLength"`
+	MaxLength  *int               `json:"maxLength"`
+	MinItems   *int               `json:"minItems"`
+}
+
+// Validate checks the parameters and JSON body of a request against its operation in the
+// document and answers mismatches with ErrInvalidRequest listing every invalid field.
+// Requests without a documented operation are passed on for the router to answer.
+func (s *Spec) Validate(c *fiber.Ctx) error {
+	op, pathValues := s.match(c.Method(), c.Path())
+	if op == nil {
+		return c.Next()
+	}
+
+	v := &validator{spec: s}
+	for _, param := range op.Parameters {
+		var value string
+		var present bool
+		switch param.In {
+		case "path":
+			value, present = pathValues[param.Name]
+		case "query":
+			value = c.Query(param.Name)
+			present = c.Context().QueryArgs().Has(param.Name)
+		case "header":
+			value = c.Get(param.Name)
+			present = value != ""
+		}
+		switch {
+		case !present && param.Required:
+			v.fail(param.Name, "is required")
+		case present:
+			v.validateParameter(param, value)
+		}
+	}
+
+	if op.RequestBody != nil {
+		if err := v.validateBody(c, op); err != nil {
+			return err
+		}
+	}
+
+	if len(v.errors) > 0 {
+		return &entity.ValidationError{Err: ErrInvalidRequest, Fields: v.errors}
+	}
+	return c.Next()
+}
+
+// validator collects the field errors of one request
+type validator struct {
+	spec   *Spec
+	errors []entity.FieldError
+}
+
+// fail records an invalid field
+func (v *validator) fail(field, message string) {
+	if field == "" {
+		field = "body"
+	}
+	v.errors = append(v.errors, entity.FieldError{Field: field, Message: message})
+}
+
+// validateParameter converts a parameter to the type of its schema and validates it
+func (v *validator) validateParameter(param *parameter, raw string) {
+	if param.Schema == nil {
+		return
+	}
+	var value any = raw
+	switch v.resolve(param.Schema).Type {
+	case "integer", "number":
+		number, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			v.fail(param.Name, "must be a number")
+			return
+		}
+		value = json.Number(strconv.FormatFloat(number, 'f', -1, 64))
+	case "boolean":
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			v.fail(param.Name, "must be true or false")
+			return
+		}
+		value = b
+	}
+	v.validate(param.Schema, value, param.Name)
+}
+
+// validateBody validates a JSON request body against the schema of its media type. Bodies of
+// undocumented media types are refused.
+func (v *validator) validateBody(c *fiber.Ctx, op *operation) error {
+	body := c.Body()
+	if len(bytes.TrimSpace(body)) == 0 {
+		if op.RequestBody.Required {
+			v.fail("body", "is required")
+		}
+		return nil
+	}
+
+	mediaType, _, err := mime.ParseMediaType(c.Get(fiber.HeaderContentType))
+	if err != nil {
+		mediaType = ""
+	}
+	content, ok := op.RequestBody.Content[mediaType]
+	if !ok {
+		supported := make([]string, 0, len(op.RequestBody.Content))
+		for candidate := range op.RequestBody.Content {
+			supported = append(supported, candidate)
+		}
+		sort.Strings(supported)
+		return fiber.NewError(fiber.StatusUnsupportedMediaType,
+			fmt.Sprintf("Content-Type must be one of %s", strings.Join(supported, ", ")))
+	}
+	if content.Schema == nil || (mediaType != fiber.MIMEApplicationJSON && !strings.HasSuffix(mediaType, "+json")) {
+		return nil
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	decoder.UseNumber()
+	var value any
+	if err := decoder.Decode(&value); err != nil {
+		v.fail("body", "is not valid JSON")
+		return nil
+	}
+	v.validate(content.Schema, value, "")
+	return nil
+}
+
+// resolve follows a schema reference to the components
+func (v *validator) resolve(s *schema) *schema {
+	for s.Ref != "" {
+		resolved, ok := v.spec.schemas[strings.TrimPrefix(s.Ref, "#/components/schemas/")]
+		if !ok {
+			return &schema{}
+		}
+		s = resolved
+	}
+	return s
+}
+
+// validate checks value against s, naming fields like items[0].sku
+func (v *validator) validate(s *schema, value any, field string) {
+	s = v.resolve(s)
+	for _, part := range s.AllOf {
+		v.validate(part, value, field)
+	}
+	if value == nil {
+		return
+	}
+
+	switch s.Type {
+	case "object":
+		object, ok := value.(map[string]any)
+		if !ok {
+			v.fail(field, "must be an object")
+			return
+		}
+		for _, name := range s.Required {
+			if _, ok := object[name]; !ok {
+				v.fail(join(field, name), "is required")
+			}
+		}
+		names := make([]string, 0, len(s.Properties))
+		for name := range s.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			if property, ok := object[name]; ok {
+				v.validate(s.Properties[name], property, join(field, name))
+			}
+		}
+	case "array":
+		array, ok := value.([]any)
+		if !ok {
+			v.fail(field, "must be an array")
+			return
+		}
+		if s.MinItems != nil && len(array) < *s.MinItems {
+			v.fail(field, fmt.Sprintf("must have at least %d items", *s.MinItems))
+		}
+		if s.Items != nil {
+			for i, item := range array {
+				v.validate(s.Items, item, fmt.Sprintf("%s[%d]", field, i))
+			}
+		}
+	case "string":
+		str, ok := value.(string)
+		if !ok {
+			v.fail(field, "must be a string")
+			return
+		}
+		v.validateString(s, str, field)
+	case "integer", "number":
+		number, ok := value.(json.Number)
+		if !ok {
+			v.fail(field, "must be a number")
+			return
+		}
+		if s.Type == "integer" {
+			if _, err := number.Int64(); err != nil {
+				v.fail(field, "must be an integer")
+				return
+			}
+		}
+		f, _ := number.Float64()
+		if s.Minimum != nil && f < *s.Minimum {
+			v.fail(field, fmt.Sprintf("must be at least %v", *s.Minimum))
+		}
+		if s.Maximum != nil && f > *s.Maximum {
+			v.fail(field, fmt.Sprintf("must be at most %v", *s.Maximum))
+		}
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			v.fail(field, "must be true or false")
+			return
+		}
+	}
+
+	if len(s.Enum) > 0 && !containsValue(s.Enum, value) {
+		allowed := make([]string, len(s.Enum))
+		for i, candidate := range s.Enum {
+			allowed[i] = fmt.Sprint(candidate)
+		}
+		v.fail(field, fmt.Sprintf("must be one of %s", strings.Join(allowed, ", ")))
+	}
+}
+
+// validateString checks the length and format of a string
+func (v *validator) validateString(s *schema, str, field string) {
+	length := len([]rune(str))
+	switch {
+	case s.MinLength != nil && *s.MinLength == 1 && length == 0:
+		v.fail(field, "must not be empty")
+	case s.MinLength != nil && length < *s.MinLength:
+		v.fail(field, fmt.Sprintf("must be at least %d characters", *s.MinLength))
+	}
+	if s.MaxLength != nil && length > *s.MaxLength {
+		v.fail(field, fmt.Sprintf("must be at most %d characters", *s.MaxLength))
+	}
+	switch s.Format {
+	case "date-time":
+		if _, err := time.Parse(time.RFC3339, str); err != nil {
+			v.fail(field, "must be an RFC 3339 timestamp")
+		}
+	case "email":
+		if _, err := mail.ParseAddress(str); err != nil {
+			v.fail(field, fmt.Sprintf("%q is not an email address", str))
+		}
+	}
+}
+
+// containsValue reports whether value is one of the enumerated values
+func containsValue(enum []any, value any) bool {
+	if number, ok := value.(json.Number); ok {
+		value, _ = number.Float64()
+	}
+	for _, candidate := range enum {
+		if reflect.DeepEqual(candidate, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// join names a property of field
+func join(field, name string) string {
+	if field == "" {
+		return name
+	}
+	return field + "." + name
+}
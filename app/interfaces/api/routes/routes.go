@@ -3,22 +3,71 @@ package routes
 import (
 	"github.com/gofiber/fiber/v2"
 	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/handlers"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/openapi"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/graphql"
 )
 
 // SetupRoutes configures all the routes for the application
-func SetupRoutes(app *fiber.App, orderHandler *handlers.OrderHandler) {
-	// API group
-	api := app.Group("/api")
+func SetupRoutes(app *fiber.App, authHandler *handlers.AuthHandler, orderHandler *handlers.OrderHandler, orderStreamHandler *handlers.OrderStreamHandler, orderTrackingHandler *handlers.OrderTrackingHandler,
+	customerHandler *handlers.CustomerHandler, pipelineHandler *handlers.PipelineHandler, adminHandler *handlers.AdminHandler,
+	graphqlHandler *graphql.Handler, spec *openapi.Spec) {
+	// API group, whose callers may authenticate with a bearer token
+	api := app.Group("/api", authHandler.Authenticate)
 
 	// Orders routes
 	orders := api.Group("/orders")
 	orders.Get("/", orderHandler.GetAllOrders)
+	orders.Get("/deleted", orderHandler.GetDeletedOrders)
+	orders.Get("/stream", orderStreamHandler.StreamOrders)
+	orders.Get("/track", orderTrackingHandler.TrackOrders())
 	orders.Get("/:id", orderHandler.GetOrder)
-	orders.Post("/", orderHandler.CreateOrder)
-	orders.Put("/:id", orderHandler.UpdateOrder)
+	orders.Get("/:id/transitions", orderHandler.GetOrderTransitions)
+	orders.Get("/:id/history", orderHandler.GetOrderHistory)
+	orders.Get("/:id/flags", orderHandler.GetOrderFlags)
+	orders.Get("/:id/tracking-token", orderTrackingHandler.GetTrackingToken)
+	orders.Post("/", orderHandler.Idempotent, orderHandler.CreateOrder)
+	orders.Put("/:id", orderHandler.Idempotent, orderHandler.UpdateOrder)
+	orders.Patch("/:id", orderHandler.Idempotent, orderHandler.PatchOrder)
+	orders.Post("/_bulk", orderHandler.Idempotent, orderHandler.BulkOrders)
 	orders.Delete("/:id", orderHandler.DeleteOrder)
+	orders.Post("/:id/restore", orderHandler.RestoreOrder)
+	orders.Delete("/:id/purge", orderHandler.PurgeOrder)
 	orders.Get("/status/:status", orderHandler.GetOrdersByStatus)
 
+	// Customers routes
+	customers := api.Group("/customers")
+	customers.Get("/", customerHandler.GetAllCustomers)
+	customers.Get("/:id", customerHandler.GetCustomer)
+	customers.Post("/", customerHandler.CreateCustomer)
+	customers.Put("/:id", customerHandler.UpdateCustomer)
+	customers.Delete("/:id", customerHandler.DeleteCustomer)
+
+	// Pipeline routes
+	pipeline := api.Group("/pipeline")
+	pipeline.Post("/snapshots", pipelineHandler.RequestSnapshot)
+	pipeline.Get("/snapshots", pipelineHandler.GetAllSnapshots)
+	pipeline.Get("/snapshots/:id", pipelineHandler.GetSnapshot)
+
+	// Admin routes, for admins only
+	admin := api.Group("/admin", authHandler.RequireAdmin)
+	admin.Get("/replication/latency", adminHandler.GetReplicationLatency)
+	admin.Get("/replication/slots", adminHandler.GetReplicationSlots)
+	admin.Delete("/replication/slots/:name", adminHandler.DropReplicationSlot)
+	admin.Get("/schema/plan", adminHandler.GetSchemaPlan)
+	admin.Post("/reconciliations", adminHandler.StartReconciliation)
+	admin.Get("/reconciliations", adminHandler.GetAllReconciliations)
+	admin.Get("/reconciliations/:id", adminHandler.GetReconciliation)
+	admin.Get("/automation/rules", adminHandler.GetAutomationRules)
+	admin.Post("/automation/runs", adminHandler.RunAutomation)
+
+	// GraphQL routes
+	app.Get("/graphql", graphqlHandler.Playground)
+	app.Post("/graphql", graphqlHandler.Query)
+
+	// API specification routes
+	app.Get("/openapi.json", spec.Document)
+	app.Get("/docs", spec.Docs)
+
 	// Health check route
 	app.Get("/health", func(c *fiber.Ctx) error {
 		return c.JSON(fiber.Map{
@@ -0,0 +1,138 @@
+// Package graphql serves the GraphQL API described by schema.graphql
+package graphql
+
+import (
+	"context"
+	_ "embed"
+	"errors"
+	"log"
+
+	"github.com/gofiber/fiber/v2"
+	gql "github.com/graph-gophers/graphql-go"
+	gqlerrors "github.com/graph-gophers/graphql-go/errors"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// schema is the GraphQL schema
+//
+//go:embed schema.graphql
+var schema string
+
+// playgroundPage is the GraphiQL page served on GET /graphql
+const playgroundPage = `<!DOCTYPE html>
+<html lang="en">
+<head>
+  <meta charset="utf-8">
+  <title>GraphiQL</title>
+  <link rel="stylesheet" href="https://unpkg.com/graphiql@3/graphiql.min.css">
+</head>
+<body style="margin: 0">
+  <div id="graphiql" style="height: 100vh"></div>
+  <script crossorigin src="https://unpkg.com/react@18/umd/react.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/react-dom@18/umd/react-dom.production.min.js"></script>
+  <script crossorigin src="https://unpkg.com/graphiql@3/graphiql.min.js"></script>
+  <script>
+    ReactDOM.createRoot(document.getElementById("graphiql")).render(
+      React.createElement(GraphiQL, { fetcher: GraphiQL.createFetcher({ url: "/graphql" }) })
+    );
+  </script>
+</body>
+</html>
+`
+
+// actorHeader names the caller recorded in the order status history
+const actorHeader = "X-Actor"
+
+// actorKey is the context key of the caller
+type actorKey struct{}
+
+// request is the body of POST /graphql
+type request struct {
+	Query         string         `json:"query"`
+	OperationName string         `json:"operationName"`
+	Variables     map[string]any `json:"variables"`
+}
+
+// Handler handles GraphQL requests
+type Handler struct {
+	schema   *gql.Schema
+	resolver *Resolver
+}
+
+// NewHandler parses the schema and creates a new Handler. Queries nested deeper than maxDepth
+// are rejected.
+func NewHandler(orderService *service.OrderService, customerService *service.CustomerService, maxDepth int) (*Handler, error) {
+	resolver := &Resolver{orderService: orderService, customerService: customerService}
+	parsed, err := gql.ParseSchema(schema, resolver, gql.MaxDepth(maxDepth))
+	if err != nil {
+		return nil, err
+	}
+	return &Handler{schema: parsed, resolver: resolver}, nil
+}
+
+// Query handles POST /graphql
+func (h *Handler) Query(c *fiber.Ctx) error {
+	req := new(request)
+	if err := c.BodyParser(req); err != nil {
+		return fiber.NewError(fiber.StatusBadRequest, err.Error())
+	}
+
+	ctx := withLoaders(c.Context(), newLoaders(h.resolver))
+	ctx = context.WithValue(ctx, actorKey{}, c.Get(actorHeader, "graphql"))
+
+	response := h.schema.Exec(ctx, req.Query, req.OperationName, req.Variables)
+	for _, queryErr := range response.Errors {
+		describeError(queryErr)
+	}
+	return c.JSON(response)
+}
+
+// Playground handles GET /graphql with a GraphiQL page
+func (h *Handler) Playground(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderContentType, fiber.MIMETextHTMLCharsetUTF8)
+	return c.SendString(playgroundPage)
+}
+
+// describeError adds the kind of a resolver error as its code extension, the counterpart of
+// the REST error handler, with the invalid fields of validation errors. Details of unexpected
+// errors are logged rather than returned.
+func describeError(queryErr *gqlerrors.QueryError) {
+	err := queryErr.ResolverError
+	if err == nil {
+		return
+	}
+
+	code := ""
+	switch {
+	case errors.Is(err, entity.ErrValidation):
+		code = "BAD_USER_INPUT"
+	case errors.Is(err, entity.ErrNotFound):
+		code = "NOT_FOUND"
+	case errors.Is(err, entity.ErrUnauthenticated):
+		code = "UNAUTHENTICATED"
+	case errors.Is(err, entity.ErrForbidden):
+		code = "FORBIDDEN"
+	case errors.Is(err, entity.ErrConflict):
+		code = "CONFLICT"
+	case errors.Is(err, entity.ErrUnavailable):
+		code = "UNAVAILABLE"
+	default:
+		log.Printf("GraphQL resolver %v failed: %v", queryErr.Path, err)
+		queryErr.Message = "internal error"
+		queryErr.Extensions = map[string]any{"code": "INTERNAL_SERVER_ERROR"}
+		return
+	}
+
+	queryErr.Message = err.Error()
+	queryErr.Extensions = map[string]any{"code": code}
+	var validation *entity.ValidationError
+	if errors.As(err, &validation) {
+		queryErr.Extensions["fields"] = validation.Fields
+	}
+}
+
+// actorFromContext returns the caller named by the X-Actor header, defaulting to graphql
+func actorFromContext(ctx context.Context) string {
+	return ctx.Value(actorKey{}).(string)
+}
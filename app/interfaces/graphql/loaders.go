@@ -0,0 +1,92 @@
+package graphql
+
+import (
+	"context"
+
+	"github.com/graph-gophers/dataloader/v7"
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// loadersKey is the context key of the request's loaders
+type loadersKey struct{}
+
+// loaders batch the lookups of related entities made while resolving one request, so a page
+// of orders loads its customers, history and flags with one query each instead of one per order
+type loaders struct {
+	customers *dataloader.Loader[string, *entity.Customer]
+	history   *dataloader.Loader[string, []entity.OrderStatusChange]
+	flags     *dataloader.Loader[string, []entity.OrderFlag]
+}
+
+// newLoaders creates the loaders of one request
+func newLoaders(r *Resolver) *loaders {
+	return &loaders{
+		customers: dataloader.NewBatchedLoader(func(ctx context.Context, ids []string) []*dataloader.Result[*entity.Customer] {
+			customers, err := r.customerService.GetCustomersByIDs(ctx, ids)
+			return batchResults(ids, err, func(id string) *entity.Customer {
+				customer, ok := customers[id]
+				if !ok {
+					return nil
+				}
+				return &customer
+			})
+		}),
+		history: dataloader.NewBatchedLoader(func(ctx context.Context, ids []string) []*dataloader.Result[[]entity.OrderStatusChange] {
+			history, err := r.orderService.GetOrdersHistory(ctx, ids)
+			return batchResults(ids, err, func(id string) []entity.OrderStatusChange { return history[id] })
+		}),
+		flags: dataloader.NewBatchedLoader(func(ctx context.Context, ids []string) []*dataloader.Result[[]entity.OrderFlag] {
+			flags, err := r.orderService.GetOrdersFlags(ctx, ids)
+			return batchResults(ids, err, func(id string) []entity.OrderFlag { return flags[id] })
+		}),
+	}
+}
+
+// prefetch queues the keys of a page of orders in the loaders of the relations the query
+// selects. Loaders batch the keys that arrive within a short wait, and the executor resolves
+// only a few orders at a time, so a large page would otherwise spread over several batches.
+// The orders' own lookups are then answered from the prefetched results.
+func (l *loaders) prefetch(ctx context.Context, orders []entity.Order) {
+	if gql.HasSelectedField(ctx, "customer") {
+		customerIDs := make([]string, len(orders))
+		for i, order := range orders {
+			customerIDs[i] = order.CustomerID
+		}
+		l.customers.LoadMany(ctx, customerIDs)
+	}
+
+	orderIDs := make([]string, len(orders))
+	for i, order := range orders {
+		orderIDs[i] = order.ID
+	}
+	if gql.HasSelectedField(ctx, "history") {
+		l.history.LoadMany(ctx, orderIDs)
+	}
+	if gql.HasSelectedField(ctx, "flags") {
+		l.flags.LoadMany(ctx, orderIDs)
+	}
+}
+
+// batchResults answers every key of a batch with its value, or with err when the batch failed
+func batchResults[V any](keys []string, err error, value func(key string) V) []*dataloader.Result[V] {
+	results := make([]*dataloader.Result[V], len(keys))
+	for i, key := range keys {
+		if err != nil {
+			results[i] = &dataloader.Result[V]{Error: err}
+			continue
+		}
+		results[i] = &dataloader.Result[V]{Data: value(key)}
+	}
+	return results
+}
+
+// withLoaders returns a context carrying the loaders of a request
+func withLoaders(ctx context.Context, l *loaders) context.Context {
+	return context.WithValue(ctx, loadersKey{}, l)
+}
+
+// loadersFromContext returns the loaders of the request
+func loadersFromContext(ctx context.Context) *loaders {
+	return ctx.Value(loadersKey{}).(*loaders)
+}
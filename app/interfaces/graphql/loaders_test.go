@@ -0,0 +1,108 @@
+package graphql
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/domain/repository"
+)
+
+// batchCounter counts the lookups of related entities
+type batchCounter struct {
+	mu      sync.Mutex
+	batches map[string]int
+}
+
+func (c *batchCounter) count(name string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.batches[name]++
+}
+
+// pagedOrderRepository serves pages of generated orders. Methods the test does not use are
+// left to the embedded interface and panic.
+type pagedOrderRepository struct {
+	repository.OrderRepository
+	counter *batchCounter
+}
+
+func (r *pagedOrderRepository) Find(_ context.Context, query entity.OrderQuery) ([]entity.Order, int64, error) {
+	orders := make([]entity.Order, query.Limit)
+	for i := range orders {
+		id := fmt.Sprint(query.Offset + i)
+		orders[i] = entity.Order{ID: id, OrderID: id, CustomerID: "customer-" + fmt.Sprint(i%7), Status: entity.OrderStatus.New}
+	}
+	return orders, int64(len(orders)), nil
+}
+
+func (r *pagedOrderRepository) FindStatusHistoryByOrderIDs(_ context.Context, ids []string) (map[string][]entity.OrderStatusChange, error) {
+	r.counter.count("history")
+	return map[string][]entity.OrderStatusChange{}, nil
+}
+
+func (r *pagedOrderRepository) FindFlagsByOrderIDs(_ context.Context, ids []string) (map[string][]entity.OrderFlag, error) {
+	r.counter.count("flags")
+	return map[string][]entity.OrderFlag{}, nil
+}
+
+// countingCustomerRepository finds every customer it is asked for
+type countingCustomerRepository struct {
+	repository.CustomerRepository
+	counter *batchCounter
+}
+
+func (r *countingCustomerRepository) FindByIDs(_ context.Context, ids []string) (map[string]entity.Customer, error) {
+	r.counter.count("customers")
+	customers := make(map[string]entity.Customer, len(ids))
+	for _, id := range ids {
+		customers[id] = entity.Customer{ID: id, Name: id}
+	}
+	return customers, nil
+}
+
+// queryOrders runs query against generated orders and returns how often each relation was loaded
+func queryOrders(t *testing.T, query string) map[string]int {
+	t.Helper()
+	counter := &batchCounter{batches: map[string]int{}}
+	dispatcher := event.NewDispatcher()
+	orderService := service.NewOrderService(&pagedOrderRepository{counter: counter}, nil, nil, nil, dispatcher, false)
+	customerService := service.NewCustomerService(&countingCustomerRepository{counter: counter}, nil, nil, dispatcher)
+	handler, err := NewHandler(orderService, customerService, 10)
+	if err != nil {
+		t.Fatalf("NewHandler() error = %v", err)
+	}
+
+	ctx := withLoaders(context.Background(), newLoaders(handler.resolver))
+	response := handler.schema.Exec(ctx, query, "", nil)
+	if len(response.Errors) > 0 {
+		t.Fatalf("query failed: %v", response.Errors)
+	}
+	return counter.batches
+}
+
+func TestOrderPageLoadsEachRelationInOneBatch(t *testing.T) {
+	// From one order to the largest page a query may ask for
+	for _, pageSize := range []int{1, 50, 500} {
+		t.Run(fmt.Sprint(pageSize), func(t *testing.T) {
+			batches := queryOrders(t, fmt.Sprintf(`{ orders(first: %d) { nodes { id customer { name } history { id } flags { id } } } }`, pageSize))
+			for _, relation := range []string{"customers", "history", "flags"} {
+				if got := batches[relation]; got != 1 {
+					t.Errorf("%s loaded in %d batches, want 1", relation, got)
+				}
+			}
+		})
+	}
+}
+
+func TestOrderPageLoadsOnlySelectedRelations(t *testing.T) {
+	batches := queryOrders(t, `{ orders(first: 500) { nodes { id history { id } } } }`)
+	want := map[string]int{"history": 1}
+	if len(batches) != len(want) || batches["history"] != 1 {
+		t.Errorf("loaded %v, want %v", batches, want)
+	}
+}
@@ -0,0 +1,246 @@
+package graphql
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// sortFields maps the OrderSortField enum to the order query sort fields
+var sortFields = map[string]string{
+	"ID":          entity.OrderSortField.ID,
+	"ORDER_ID":    entity.OrderSortField.OrderID,
+	"CUSTOMER_ID": entity.OrderSortField.CustomerID,
+	"STATUS":      entity.OrderSortField.Status,
+	"CREATED_AT":  entity.OrderSortField.CreatedAt,
+	"UPDATED_AT":  entity.OrderSortField.UpdatedAt,
+}
+
+// Long is the Long scalar, a 64-bit integer
+type Long int64
+
+// ImplementsGraphQLType maps Long to the Long scalar
+func (Long) ImplementsGraphQLType(name string) bool {
+	return name == "Long"
+}
+
+// UnmarshalGraphQL reads a Long from a literal or variable
+func (l *Long) UnmarshalGraphQL(input interface{}) error {
+	switch value := input.(type) {
+	case int32:
+		*l = Long(value)
+	case int64:
+		*l = Long(value)
+	case float64:
+		if value != float64(int64(value)) {
+			return fmt.Errorf("%v is not an integer", value)
+		}
+		*l = Long(value)
+	case string:
+		n, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			return fmt.Errorf("%q is not an integer", value)
+		}
+		*l = Long(n)
+	default:
+		return fmt.Errorf("wrong type for Long: %T", input)
+	}
+	return nil
+}
+
+// Resolver is the root resolver of the schema
+type Resolver struct {
+	orderService    *service.OrderService
+	customerService *service.CustomerService
+}
+
+// orderFilter is the OrderFilter input
+type orderFilter struct {
+	Statuses    *[]string
+	CustomerID  *gql.ID
+	CreatedFrom *gql.Time
+	CreatedTo   *gql.Time
+	UpdatedFrom *gql.Time
+	UpdatedTo   *gql.Time
+}
+
+// orderSort is the OrderSort input
+type orderSort struct {
+	Field      string
+	Descending bool
+}
+
+// moneyInput is the MoneyInput input
+type moneyInput struct {
+	Amount   Long
+	Currency string
+}
+
+// orderItemInput is the OrderItemInput input
+type orderItemInput struct {
+	SKU       string
+	Quantity  int32
+	UnitPrice moneyInput
+}
+
+// orderInput is the OrderInput input
+type orderInput struct {
+	OrderID    *string
+	CustomerID gql.ID
+	Status     *string
+	Items      *[]orderItemInput
+	Version    *Long
+}
+
+// toOrder converts the input to an order
+func (input *orderInput) toOrder(id string) *entity.Order {
+	order := &entity.Order{ID: id, CustomerID: string(input.CustomerID)}
+	if input.OrderID != nil {
+		order.OrderID = *input.OrderID
+	}
+	if input.Status != nil {
+		order.Status = *input.Status
+	}
+	if input.Version != nil {
+		order.Version = int64(*input.Version)
+	}
+	if input.Items != nil {
+		for _, item := range *input.Items {
+			order.Items = append(order.Items, entity.OrderItem{
+				SKU:       item.SKU,
+				Quantity:  int(item.Quantity),
+				UnitPrice: entity.Money{Amount: int64(item.UnitPrice.Amount), Currency: item.UnitPrice.Currency},
+			})
+		}
+	}
+	return order
+}
+
+// Order resolves Query.order
+func (r *Resolver) Order(ctx context.Context, args struct{ ID gql.ID }) (*orderResolver, error) {
+	order, err := r.orderService.GetOrderByID(ctx, string(args.ID))
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &orderResolver{order: *order}, nil
+}
+
+// Orders resolves Query.orders
+func (r *Resolver) Orders(ctx context.Context, args struct {
+	Filter *orderFilter
+	Sort   *orderSort
+	First  int32
+	Offset int32
+	Source string
+}) (*orderConnectionResolver, error) {
+	query := entity.OrderQuery{Limit: int(args.First), Offset: int(args.Offset)}
+	if filter := args.Filter; filter != nil {
+		if filter.Statuses != nil {
+			query.Statuses = *filter.Statuses
+		}
+		if filter.CustomerID != nil {
+			query.CustomerID = string(*filter.CustomerID)
+		}
+		query.CreatedFrom = timeValue(filter.CreatedFrom)
+		query.CreatedTo = timeValue(filter.CreatedTo)
+		query.UpdatedFrom = timeValue(filter.UpdatedFrom)
+		query.UpdatedTo = timeValue(filter.UpdatedTo)
+	}
+	if args.Sort != nil {
+		query.Sort = sortFields[args.Sort.Field]
+		query.Descending = args.Sort.Descending
+	}
+
+	var page *entity.OrderPage
+	var err error
+	if args.Source == "SEARCH" {
+		page, err = r.orderService.SearchOrders(ctx, query)
+	} else {
+		page, err = r.orderService.GetOrders(ctx, query)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &orderConnectionResolver{page: page}, nil
+}
+
+// Customer resolves Query.customer
+func (r *Resolver) Customer(ctx context.Context, args struct{ ID gql.ID }) (*customerResolver, error) {
+	customer, err := r.customerService.GetCustomerByID(ctx, string(args.ID))
+	if errors.Is(err, entity.ErrNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &customerResolver{customer: *customer}, nil
+}
+
+// Customers resolves Query.customers
+func (r *Resolver) Customers(ctx context.Context) ([]*customerResolver, error) {
+	customers, err := r.customerService.GetAllCustomers(ctx)
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*customerResolver, len(customers))
+	for i, customer := range customers {
+		resolvers[i] = &customerResolver{customer: customer}
+	}
+	return resolvers, nil
+}
+
+// CreateOrder resolves Mutation.createOrder
+func (r *Resolver) CreateOrder(ctx context.Context, args struct{ Input orderInput }) (*orderResolver, error) {
+	order := args.Input.toOrder("")
+	if err := r.orderService.CreateOrder(ctx, order, actorFromContext(ctx)); err != nil {
+		return nil, err
+	}
+	return &orderResolver{order: *order}, nil
+}
+
+// UpdateOrder resolves Mutation.updateOrder
+func (r *Resolver) UpdateOrder(ctx context.Context, args struct {
+	ID     gql.ID
+	Input  orderInput
+	Reason *string
+}) (*orderResolver, error) {
+	reason := ""
+	if args.Reason != nil {
+		reason = *args.Reason
+	}
+	order := args.Input.toOrder(string(args.ID))
+	if err := r.orderService.ReplaceOrder(ctx, order, actorFromContext(ctx), reason); err != nil {
+		return nil, err
+	}
+
+	updated, err := r.orderService.GetOrderByID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	return &orderResolver{order: *updated}, nil
+}
+
+// DeleteOrder resolves Mutation.deleteOrder
+func (r *Resolver) DeleteOrder(ctx context.Context, args struct{ ID gql.ID }) (bool, error) {
+	if err := r.orderService.DeleteOrder(ctx, string(args.ID), actorFromContext(ctx)); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// RestoreOrder resolves Mutation.restoreOrder
+func (r *Resolver) RestoreOrder(ctx context.Context, args struct{ ID gql.ID }) (*orderResolver, error) {
+	order, err := r.orderService.RestoreOrder(ctx, string(args.ID), actorFromContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	return &orderResolver{order: *order}, nil
+}
@@ -0,0 +1,165 @@
+package graphql
+
+import (
+	"context"
+	"time"
+
+	gql "github.com/graph-gophers/graphql-go"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// orderConnectionResolver resolves OrderConnection
+type orderConnectionResolver struct {
+	page *entity.OrderPage
+}
+
+func (r *orderConnectionResolver) Nodes(ctx context.Context) []*orderResolver {
+	loadersFromContext(ctx).prefetch(ctx, r.page.Orders)
+	nodes := make([]*orderResolver, len(r.page.Orders))
+	for i, order := range r.page.Orders {
+		nodes[i] = &orderResolver{order: order}
+	}
+	return nodes
+}
+
+func (r *orderConnectionResolver) TotalCount() Long { return Long(r.page.Total) }
+func (r *orderConnectionResolver) Limit() int32     { return int32(r.page.Limit) }
+func (r *orderConnectionResolver) Offset() int32    { return int32(r.page.Offset) }
+
+func (r *orderConnectionResolver) HasNextPage() bool {
+	return int64(r.page.Offset+len(r.page.Orders)) < r.page.Total
+}
+
+// orderResolver resolves Order; its customer, history and flags are batch-loaded
+type orderResolver struct {
+	order entity.Order
+}
+
+func (r *orderResolver) ID() gql.ID            { return gql.ID(r.order.ID) }
+func (r *orderResolver) OrderID() string       { return r.order.OrderID }
+func (r *orderResolver) CustomerID() gql.ID    { return gql.ID(r.order.CustomerID) }
+func (r *orderResolver) CustomerName() string  { return r.order.CustomerName }
+func (r *orderResolver) CustomerEmail() string { return r.order.CustomerEmail }
+func (r *orderResolver) Status() string        { return r.order.Status }
+func (r *orderResolver) Transitions() []string { return entity.NextOrderStatuses(r.order.Status) }
+func (r *orderResolver) Total() *moneyResolver { return &moneyResolver{money: r.order.Total} }
+func (r *orderResolver) Version() Long         { return Long(r.order.Version) }
+func (r *orderResolver) CreatedAt() gql.Time   { return gql.Time{Time: r.order.CreatedAt} }
+func (r *orderResolver) UpdatedAt() gql.Time   { return gql.Time{Time: r.order.UpdatedAt} }
+func (r *orderResolver) DeletedAt() *gql.Time  { return timeOrNil(r.order.DeletedAt) }
+
+func (r *orderResolver) Items() []*orderItemResolver {
+	items := make([]*orderItemResolver, len(r.order.Items))
+	for i, item := range r.order.Items {
+		items[i] = &orderItemResolver{item: item}
+	}
+	return items
+}
+
+func (r *orderResolver) Customer(ctx context.Context) (*customerResolver, error) {
+	customer, err := loadersFromContext(ctx).customers.Load(ctx, r.order.CustomerID)()
+	if err != nil || customer == nil {
+		return nil, err
+	}
+	return &customerResolver{customer: *customer}, nil
+}
+
+func (r *orderResolver) History(ctx context.Context) ([]*statusChangeResolver, error) {
+	history, err := loadersFromContext(ctx).history.Load(ctx, r.order.ID)()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*statusChangeResolver, len(history))
+	for i, change := range history {
+		resolvers[i] = &statusChangeResolver{change: change}
+	}
+	return resolvers, nil
+}
+
+func (r *orderResolver) Flags(ctx context.Context) ([]*flagResolver, error) {
+	flags, err := loadersFromContext(ctx).flags.Load(ctx, r.order.ID)()
+	if err != nil {
+		return nil, err
+	}
+	resolvers := make([]*flagResolver, len(flags))
+	for i, flag := range flags {
+		resolvers[i] = &flagResolver{flag: flag}
+	}
+	return resolvers, nil
+}
+
+// orderItemResolver resolves OrderItem
+type orderItemResolver struct {
+	item entity.OrderItem
+}
+
+func (r *orderItemResolver) ID() gql.ID      { return gql.ID(r.item.ID) }
+func (r *orderItemResolver) SKU() string     { return r.item.SKU }
+func (r *orderItemResolver) Quantity() int32 { return int32(r.item.Quantity) }
+
+func (r *orderItemResolver) UnitPrice() *moneyResolver {
+	return &moneyResolver{money: r.item.UnitPrice}
+}
+
+func (r *orderItemResolver) LineTotal() *moneyResolver {
+	return &moneyResolver{money: r.item.LineTotal}
+}
+
+// moneyResolver resolves Money
+type moneyResolver struct {
+	money entity.Money
+}
+
+func (r *moneyResolver) Amount() Long     { return Long(r.money.Amount) }
+func (r *moneyResolver) Currency() string { return r.money.Currency }
+
+// customerResolver resolves Customer
+type customerResolver struct {
+	customer entity.Customer
+}
+
+func (r *customerResolver) ID() gql.ID          { return gql.ID(r.customer.ID) }
+func (r *customerResolver) Name() string        { return r.customer.Name }
+func (r *customerResolver) Email() string       { return r.customer.Email }
+func (r *customerResolver) Phone() string       { return r.customer.Phone }
+func (r *customerResolver) CreatedAt() gql.Time { return gql.Time{Time: r.customer.CreatedAt} }
+func (r *customerResolver) UpdatedAt() gql.Time { return gql.Time{Time: r.customer.UpdatedAt} }
+
+// statusChangeResolver resolves OrderStatusChange
+type statusChangeResolver struct {
+	change entity.OrderStatusChange
+}
+
+func (r *statusChangeResolver) ID() gql.ID          { return gql.ID(r.change.ID) }
+func (r *statusChangeResolver) FromStatus() string  { return r.change.FromStatus }
+func (r *statusChangeResolver) ToStatus() string    { return r.change.ToStatus }
+func (r *statusChangeResolver) Actor() string       { return r.change.Actor }
+func (r *statusChangeResolver) Reason() string      { return r.change.Reason }
+func (r *statusChangeResolver) ChangedAt() gql.Time { return gql.Time{Time: r.change.ChangedAt} }
+
+// flagResolver resolves OrderFlag
+type flagResolver struct {
+	flag entity.OrderFlag
+}
+
+func (r *flagResolver) ID() gql.ID          { return gql.ID(r.flag.ID) }
+func (r *flagResolver) Rule() string        { return r.flag.Rule }
+func (r *flagResolver) Reason() string      { return r.flag.Reason }
+func (r *flagResolver) FlaggedAt() gql.Time { return gql.Time{Time: r.flag.FlaggedAt} }
+
+// timeOrNil converts an optional time
+func timeOrNil(t *time.Time) *gql.Time {
+	if t == nil {
+		return nil
+	}
+	return &gql.Time{Time: *t}
+}
+
+// timeValue converts an optional input time
+func timeValue(t *gql.Time) *time.Time {
+	if t == nil {
+		return nil
+	}
+	value := t.Time
+	return &value
+}
@@ -0,0 +1,78 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/metadata"
+)
+
+// authorizationKey is the metadata key carrying the bearer token of a call
+const authorizationKey = "authorization"
+
+// principalKey is the context key of the authenticated principal of a call
+type principalKey struct{}
+
+// authenticator resolves the principal of calls made with a bearer token, the counterpart of
+// the REST AuthHandler. Calls without the authorization key continue anonymously; invalid
+// tokens are answered with Unauthenticated.
+type authenticator struct {
+	authService *service.AuthService
+}
+
+// authenticate returns ctx carrying the principal of the call
+func (a *authenticator) authenticate(ctx context.Context) (context.Context, error) {
+	values := metadata.ValueFromIncomingContext(ctx, authorizationKey)
+	if len(values) == 0 || values[0] == "" {
+		return ctx, nil
+	}
+
+	scheme, token, _ := strings.Cut(values[0], " ")
+	if !strings.EqualFold(scheme, "Bearer") || token == "" {
+		return nil, fmt.Errorf("%w: expected a bearer token", entity.ErrInvalidCredentials)
+	}
+	principal, err := a.authService.Authenticate(strings.TrimSpace(token))
+	if err != nil {
+		return nil, err
+	}
+	return context.WithValue(ctx, principalKey{}, principal), nil
+}
+
+// unary authenticates unary calls
+func (a *authenticator) unary(ctx context.Context, req any, _ *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	ctx, err := a.authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+// stream authenticates streaming calls
+func (a *authenticator) stream(srv any, stream grpc.ServerStream, _ *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := a.authenticate(stream.Context())
+	if err != nil {
+		return err
+	}
+	return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+}
+
+// authenticatedStream is a server stream whose context carries the principal of the call
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+// Context returns the context of the call carrying its principal
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
+
+// callPrincipal returns the authenticated principal of a call, or nil when it is anonymous
+func callPrincipal(ctx context.Context) *entity.Principal {
+	principal, _ := ctx.Value(principalKey{}).(*entity.Principal)
+	return principal
+}
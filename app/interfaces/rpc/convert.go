@@ -0,0 +1,143 @@
+package rpc
+
+import (
+	"time"
+
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/rpc/ordersv1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// toProtoOrder converts an order to its message
+func toProtoOrder(order *entity.Order) *ordersv1.Order {
+	message := &ordersv1.Order{
+		Id:            order.ID,
+		OrderId:       order.OrderID,
+		CustomerId:    order.CustomerID,
+		CustomerName:  order.CustomerName,
+		CustomerEmail: order.CustomerEmail,
+		Status:        order.Status,
+		Items:         make([]*ordersv1.OrderItem, len(order.Items)),
+		Total:         toProtoMoney(order.Total),
+		Version:       order.Version,
+		CreatedAt:     toProtoTime(order.CreatedAt),
+		UpdatedAt:     toProtoTime(order.UpdatedAt),
+	}
+	for i, item := range order.Items {
+		message.Items[i] = &ordersv1.OrderItem{
+			Id:        item.ID,
+			Sku:       item.SKU,
+			Quantity:  int32(item.Quantity),
+			UnitPrice: toProtoMoney(item.UnitPrice),
+			LineTotal: toProtoMoney(item.LineTotal),
+		}
+	}
+	if order.DeletedAt != nil {
+		message.DeletedAt = timestamppb.New(*order.DeletedAt)
+	}
+	return message
+}
+
+// toProtoOrders converts a list of orders to messages
+func toProtoOrders(orders []entity.Order) []*ordersv1.Order {
+	messages := make([]*ordersv1.Order, len(orders))
+	for i := range orders {
+		messages[i] = toProtoOrder(&orders[i])
+	}
+	return messages
+}
+
+// fromProtoOrder converts the writable fields of an order message
+func fromProtoOrder(message *ordersv1.Order) *entity.Order {
+	order := &entity.Order{
+		ID:         message.GetId(),
+		OrderID:    message.GetOrderId(),
+		CustomerID: message.GetCustomerId(),
+		Status:     message.GetStatus(),
+		Version:    message.GetVersion(),
+	}
+	for _, item := range message.GetItems() {
+		order.Items = append(order.Items, entity.OrderItem{
+			SKU:       item.GetSku(),
+			Quantity:  int(item.GetQuantity()),
+			UnitPrice: fromProtoMoney(item.GetUnitPrice()),
+		})
+	}
+	return order
+}
+
+// toProtoEvent converts an order event to its message
+func toProtoEvent(event *entity.OrderEvent) *ordersv1.OrderEvent {
+	return &ordersv1.OrderEvent{
+		Id:         event.ID,
+		Type:       event.Type,
+		OrderId:    event.OrderID,
+		Actor:      event.Actor,
+		OccurredAt: toProtoTime(event.OccurredAt),
+		Order:      toProtoOrder(&event.Order),
+		FromStatus: event.FromStatus,
+		ToStatus:   event.ToStatus,
+		Reason:     event.Reason,
+	}
+}
+
+// toProtoFeedEvent converts an event of the outbox feed, with its position as the resume token
+func toProtoFeedEvent(held event.FeedEvent) *ordersv1.OrderEvent {
+	protoEvent := toProtoEvent(&held.Event)
+	protoEvent.ResumeToken = held.ID
+	return protoEvent
+}
+
+// fromProtoQuery converts a list request to an order query
+func fromProtoQuery(request *ordersv1.ListOrdersRequest) entity.OrderQuery {
+	return entity.OrderQuery{
+		Statuses:    request.GetStatuses(),
+		CustomerID:  request.GetCustomerId(),
+		CreatedFrom: fromProtoTime(request.GetCreatedFrom()),
+		CreatedTo:   fromProtoTime(request.GetCreatedTo()),
+		UpdatedFrom: fromProtoTime(request.GetUpdatedFrom()),
+		UpdatedTo:   fromProtoTime(request.GetUpdatedTo()),
+		Sort:        request.GetSort(),
+		Descending:  request.GetDescending(),
+		Limit:       int(request.GetLimit()),
+		Offset:      int(request.GetOffset()),
+	}
+}
+
+// toProtoPage converts a page of orders to a list response
+func toProtoPage(page *entity.OrderPage) *ordersv1.ListOrdersResponse {
+	return &ordersv1.ListOrdersResponse{
+		Orders: toProtoOrders(page.Orders),
+		Total:  page.Total,
+		Limit:  int32(page.Limit),
+		Offset: int32(page.Offset),
+	}
+}
+
+// toProtoMoney converts money to its message
+func toProtoMoney(money entity.Money) *ordersv1.Money {
+	return &ordersv1.Money{Amount: money.Amount, Currency: money.Currency}
+}
+
+// fromProtoMoney converts a money message, treating a missing one as zero
+func fromProtoMoney(message *ordersv1.Money) entity.Money {
+	return entity.Money{Amount: message.GetAmount(), Currency: message.GetCurrency()}
+}
+
+// toProtoTime converts a time, leaving the zero time unset
+func toProtoTime(t time.Time) *timestamppb.Timestamp {
+	if t.IsZero() {
+		return nil
+	}
+	return timestamppb.New(t)
+}
+
+// fromProtoTime converts an optional timestamp
+func fromProtoTime(timestamp *timestamppb.Timestamp) *time.Time {
+	if timestamp == nil {
+		return nil
+	}
+	t := timestamp.AsTime()
+	return &t
+}
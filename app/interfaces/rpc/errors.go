@@ -0,0 +1,82 @@
+package rpc
+
+import (
+	"context"
+	"errors"
+	"log"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// unaryErrors converts the errors of unary calls to statuses
+func unaryErrors(ctx context.Context, req any, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (any, error) {
+	resp, err := handler(ctx, req)
+	if err != nil {
+		return nil, toStatus(info.FullMethod, err)
+	}
+	return resp, nil
+}
+
+// streamErrors converts the errors of streaming calls to statuses
+func streamErrors(srv any, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	if err := handler(srv, stream); err != nil {
+		return toStatus(info.FullMethod, err)
+	}
+	return nil
+}
+
+// toStatus answers an error with the status code its kind maps to, the counterpart of the
+// REST error handler. Invalid fields are attached as BadRequest details; details of
+// unexpected errors are logged rather than returned.
+func toStatus(method string, err error) error {
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var code codes.Code
+	switch {
+	case errors.Is(err, context.Canceled):
+		code = codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded):
+		code = codes.DeadlineExceeded
+	case errors.Is(err, entity.ErrValidation):
+		code = codes.InvalidArgument
+	case errors.Is(err, entity.ErrNotFound):
+		code = codes.NotFound
+	case errors.Is(err, entity.ErrUnauthenticated):
+		code = codes.Unauthenticated
+	case errors.Is(err, entity.ErrForbidden):
+		code = codes.PermissionDenied
+	case errors.Is(err, entity.ErrDuplicateOrder):
+		code = codes.AlreadyExists
+	case errors.Is(err, entity.ErrOrderVersionConflict):
+		code = codes.Aborted
+	case errors.Is(err, entity.ErrConflict):
+		code = codes.FailedPrecondition
+	case errors.Is(err, entity.ErrUnavailable):
+		code = codes.Unavailable
+	default:
+		log.Printf("%s failed: %v", method, err)
+		return status.Error(codes.Internal, "internal error")
+	}
+
+	st := status.New(code, err.Error())
+	var validation *entity.ValidationError
+	if errors.As(err, &validation) {
+		details := &errdetails.BadRequest{}
+		for _, field := range validation.Fields {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field.Field,
+				Description: field.Message,
+			})
+		}
+		if withDetails, detailsErr := st.WithDetails(details); detailsErr == nil {
+			st = withDetails
+		}
+	}
+	return st.Err()
+}
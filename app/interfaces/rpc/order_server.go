@@ -0,0 +1,175 @@
+package rpc
+
+import (
+	"context"
+	"fmt"
+	"slices"
+
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/rpc/ordersv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+)
+
+// actorKey is the metadata key naming the caller recorded in the order status history
+const actorKey = "x-actor"
+
+// OrderServer implements the gRPC OrderService on top of the order service
+type OrderServer struct {
+	ordersv1.UnimplementedOrderServiceServer
+	orderService *service.OrderService
+	feed         *event.Feed
+	// watchBuffer is how many events a watcher may fall behind before its stream is ended
+	watchBuffer int
+}
+
+// NewOrderServer creates a new OrderServer
+func NewOrderServer(orderService *service.OrderService, feed *event.Feed, watchBuffer int) *OrderServer {
+	return &OrderServer{
+		orderService: orderService,
+		feed:         feed,
+		watchBuffer:  watchBuffer,
+	}
+}
+
+// GetOrder returns an order by its id
+func (s *OrderServer) GetOrder(ctx context.Context, request *ordersv1.GetOrderRequest) (*ordersv1.Order, error) {
+	order, err := s.orderService.GetOrderByID(ctx, request.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toProtoOrder(order), nil
+}
+
+// ListOrders returns a page of orders from PostgreSQL
+func (s *OrderServer) ListOrders(ctx context.Context, request *ordersv1.ListOrdersRequest) (*ordersv1.ListOrdersResponse, error) {
+	page, err := s.orderService.GetOrders(ctx, fromProtoQuery(request))
+	if err != nil {
+		return nil, err
+	}
+	return toProtoPage(page), nil
+}
+
+// SearchOrders returns a page of orders from the search index
+func (s *OrderServer) SearchOrders(ctx context.Context, request *ordersv1.ListOrdersRequest) (*ordersv1.ListOrdersResponse, error) {
+	page, err := s.orderService.SearchOrders(ctx, fromProtoQuery(request))
+	if err != nil {
+		return nil, err
+	}
+	return toProtoPage(page), nil
+}
+
+// CreateOrder creates an order
+func (s *OrderServer) CreateOrder(ctx context.Context, request *ordersv1.CreateOrderRequest) (*ordersv1.Order, error) {
+	if request.GetOrder() == nil {
+		return nil, entity.NewFieldError(entity.ErrInvalidOrder, "order", "is required")
+	}
+	order := fromProtoOrder(request.GetOrder())
+	if err := s.orderService.CreateOrder(ctx, order, callActor(ctx)); err != nil {
+		return nil, err
+	}
+	return toProtoOrder(order), nil
+}
+
+// UpdateOrder replaces the writable fields of an order
+func (s *OrderServer) UpdateOrder(ctx context.Context, request *ordersv1.UpdateOrderRequest) (*ordersv1.Order, error) {
+	if request.GetOrder().GetId() == "" {
+		return nil, entity.NewFieldError(entity.ErrInvalidOrder, "order.id", "is required")
+	}
+	order := fromProtoOrder(request.GetOrder())
+	if err := s.orderService.ReplaceOrder(ctx, order, callActor(ctx), request.GetReason()); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.orderService.GetOrderByID(ctx, order.ID)
+	if err != nil {
+		return nil, err
+	}
+	return toProtoOrder(updated), nil
+}
+
+// DeleteOrder moves an order to the trash
+func (s *OrderServer) DeleteOrder(ctx context.Context, request *ordersv1.DeleteOrderRequest) (*ordersv1.DeleteOrderResponse, error) {
+	if err := s.orderService.DeleteOrder(ctx, request.GetId(), callActor(ctx)); err != nil {
+		return nil, err
+	}
+	return &ordersv1.DeleteOrderResponse{}, nil
+}
+
+// WatchOrders streams the events of the order changes made through any replica, read from the
+// outbox, until the client goes away. A client that watches again with the resume token of the
+// last event it received gets the events it missed; when those are no longer in the outbox or
+// too many, the stream starts with a refetch message. A client that falls more than the watch
+// buffer behind is disconnected rather than slowing down the writers.
+func (s *OrderServer) WatchOrders(request *ordersv1.WatchOrdersRequest, stream grpc.ServerStreamingServer[ordersv1.OrderEvent]) error {
+	for i, orderStatus := range request.GetStatuses() {
+		if !entity.IsValidOrderStatus(orderStatus) {
+			return entity.NewFieldError(entity.ErrInvalidOrderStatus, fmt.Sprintf("statuses[%d]", i),
+				fmt.Sprintf("%q is not an order status", orderStatus))
+		}
+	}
+
+	ctx := stream.Context()
+	sub, missed, resumed, err := s.feed.Subscribe(ctx, request.GetResumeToken(), s.watchBuffer, func(event *entity.OrderEvent) bool {
+		return watches(request, event)
+	})
+	if err != nil {
+		return err
+	}
+	defer s.feed.Unsubscribe(sub)
+
+	if request.GetResumeToken() != "" && !resumed {
+		if err := stream.Send(&ordersv1.OrderEvent{Refetch: true}); err != nil {
+			return err
+		}
+	}
+	for _, held := range missed {
+		if err := stream.Send(toProtoFeedEvent(held)); err != nil {
+			return err
+		}
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-sub.Done():
+			return status.Error(codes.ResourceExhausted, "watcher fell too far behind; watch again with the last resume token")
+		case held := <-sub.Events():
+			if err := stream.Send(toProtoFeedEvent(held)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// watches reports whether a watch request selects an event
+func watches(request *ordersv1.WatchOrdersRequest, event *entity.OrderEvent) bool {
+	if ids := request.GetOrderIds(); len(ids) > 0 && !slices.Contains(ids, event.OrderID) {
+		return false
+	}
+	if customerID := request.GetCustomerId(); customerID != "" && event.Order.CustomerID != customerID {
+		return false
+	}
+	if eventTypes := request.GetEventTypes(); len(eventTypes) > 0 && !slices.Contains(eventTypes, event.Type) {
+		return false
+	}
+	// A status change out of the watched statuses is sent too, so clients can drop the order
+	statuses := request.GetStatuses()
+	return len(statuses) == 0 || slices.Contains(statuses, event.Order.Status) || slices.Contains(statuses, event.FromStatus)
+}
+
+// callActor returns the authenticated principal of a call, or for anonymous calls the caller
+// named by the x-actor metadata, defaulting to grpc
+func callActor(ctx context.Context) string {
+	if principal := callPrincipal(ctx); principal != nil {
+		return principal.Scope()
+	}
+	if values := metadata.ValueFromIncomingContext(ctx, actorKey); len(values) > 0 && values[0] != "" {
+		return values[0]
+	}
+	return "grpc"
+}
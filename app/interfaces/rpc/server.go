@@ -0,0 +1,33 @@
+// Package rpc serves the gRPC API described by proto/orders/v1/orders.proto.
+//
+// Regenerate the ordersv1 package after changing the proto file with
+//
+//	protoc --proto_path=proto --go_out=. --go_opt=module=github.com/mehmetymw/debezium-postgres-es \
+//		--go-grpc_out=. --go-grpc_opt=module=github.com/mehmetymw/debezium-postgres-es orders/v1/orders.proto
+//
+// run from the app directory.
+package rpc
+
+import (
+	"github.com/mehmetymw/debezium-postgres-es/application/service"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/rpc/ordersv1"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+	"google.golang.org/grpc/reflection"
+)
+
+// NewServer creates a gRPC server serving the order service, the standard health service and
+// server reflection for tools like grpcurl. Calls are authenticated with authService like the
+// REST API.
+func NewServer(orderServer *OrderServer, authService *service.AuthService) *grpc.Server {
+	auth := &authenticator{authService: authService}
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(unaryErrors, auth.unary),
+		grpc.ChainStreamInterceptor(streamErrors, auth.stream),
+	)
+	ordersv1.RegisterOrderServiceServer(server, orderServer)
+	healthpb.RegisterHealthServer(server, health.NewServer())
+	reflection.Register(server)
+	return server
+}
@@ -0,0 +1,28 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// CustomerService defines the interface for customer operations
+type CustomerService interface {
+	// GetAllCustomers retrieves all customers
+	GetAllCustomers(ctx context.Context) ([]entity.Customer, error)
+
+	// GetCustomerByID retrieves a customer by its ID
+	GetCustomerByID(ctx context.Context, id string) (*entity.Customer, error)
+
+	// GetCustomersByIDs retrieves several customers at once, keyed by ID
+	GetCustomersByIDs(ctx context.Context, ids []string) (map[string]entity.Customer, error)
+
+	// CreateCustomer creates a new customer
+	CreateCustomer(ctx context.Context, customer *entity.Customer) error
+
+	// UpdateCustomer updates an existing customer on behalf of actor
+	UpdateCustomer(ctx context.Context, customer *entity.Customer, actor string) error
+
+	// DeleteCustomer deletes a customer by its ID
+	DeleteCustomer(ctx context.Context, id string) error
+}
@@ -11,18 +11,55 @@ type OrderService interface {
 	// GetAllOrders retrieves all orders
 	GetAllOrders(ctx context.Context) ([]entity.Order, error)
 
+	// GetOrders retrieves the page of orders selected by query from PostgreSQL
+	GetOrders(ctx context.Context, query entity.OrderQuery) (*entity.OrderPage, error)
+
+	// SearchOrders retrieves the page of orders selected by query from the search index
+	SearchOrders(ctx context.Context, query entity.OrderQuery) (*entity.OrderPage, error)
+
 	// GetOrderByID retrieves an order by its ID
 	GetOrderByID(ctx context.Context, id string) (*entity.Order, error)
 
 	// GetOrdersByStatus retrieves orders by status
 	GetOrdersByStatus(ctx context.Context, status string) ([]entity.Order, error)
 
-	// CreateOrder creates a new order
-	CreateOrder(ctx context.Context, order *entity.Order) error
+	// GetOrderTransitions retrieves an order and the statuses it may move to next
+	GetOrderTransitions(ctx context.Context, id string) (*entity.Order, []string, error)
+
+	// GetOrderHistory retrieves the status changes of an order, oldest first
+	GetOrderHistory(ctx context.Context, id string) ([]entity.OrderStatusChange, error)
+
+	// GetOrderFlags retrieves the flags raised on an order by automation rules
+	GetOrderFlags(ctx context.Context, id string) ([]entity.OrderFlag, error)
+
+	// GetOrdersHistory retrieves the status changes of several orders at once, keyed by order ID
+	GetOrdersHistory(ctx context.Context, ids []string) (map[string][]entity.OrderStatusChange, error)
+
+	// GetOrdersFlags retrieves the flags of several orders at once, keyed by order ID
+	GetOrdersFlags(ctx context.Context, ids []string) (map[string][]entity.OrderFlag, error)
+
+	// CreateOrder creates a new order, recording its initial status as set by actor
+	CreateOrder(ctx context.Context, order *entity.Order, actor string) error
+
+	// UpdateOrder updates an existing order, recording a status change with actor and reason
+	UpdateOrder(ctx context.Context, order *entity.Order, actor, reason string) error
+
+	// ReplaceOrder replaces the writable fields of an existing order, recording a status change with actor and reason
+	ReplaceOrder(ctx context.Context, order *entity.Order, actor, reason string) error
+
+	// BulkOrders applies a batch of create, update and delete operations on behalf of actor,
+	// in one transaction when atomic
+	BulkOrders(ctx context.Context, operations []entity.BulkOrderOperation, actor string, atomic bool) []entity.BulkOrderResult
+
+	// DeleteOrder deletes an order by its ID on behalf of actor
+	DeleteOrder(ctx context.Context, id, actor string) error
+
+	// GetDeletedOrders retrieves all soft-deleted orders
+	GetDeletedOrders(ctx context.Context) ([]entity.Order, error)
 
-	// UpdateOrder updates an existing order
-	UpdateOrder(ctx context.Context, order *entity.Order) error
+	// RestoreOrder undeletes a soft-deleted order on behalf of actor
+	RestoreOrder(ctx context.Context, id, actor string) (*entity.Order, error)
 
-	// DeleteOrder deletes an order by its ID
-	DeleteOrder(ctx context.Context, id string) error
+	// PurgeOrder permanently removes a soft-deleted order on behalf of actor
+	PurgeOrder(ctx context.Context, id, actor string) error
 }
@@ -0,0 +1,22 @@
+package services
+
+import (
+	"context"
+
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
+)
+
+// SnapshotService defines the interface for incremental snapshot operations
+type SnapshotService interface {
+	// GetAllSnapshots retrieves all snapshot requests
+	GetAllSnapshots(ctx context.Context) ([]entity.SnapshotRequest, error)
+
+	// GetSnapshotByID retrieves a snapshot request by its ID
+	GetSnapshotByID(ctx context.Context, id string) (*entity.SnapshotRequest, error)
+
+	// RequestSnapshot sends an incremental snapshot signal to Debezium
+	RequestSnapshot(ctx context.Context, request *entity.SnapshotRequest) error
+
+	// TrackProgress updates open snapshot requests from Debezium watermark signals
+	TrackProgress(ctx context.Context) error
+}
@@ -1,19 +1,33 @@
 package main
 
 import (
+	"context"
+	"crypto/rand"
+	"encoding/json"
+	"errors"
+	"flag"
 	"fmt"
 	"log"
+	"net"
+	"os"
+	"strings"
 
 	"github.com/gofiber/fiber/v2"
 	"github.com/gofiber/fiber/v2/middleware/cors"
 	"github.com/gofiber/fiber/v2/middleware/logger"
 	"github.com/gofiber/fiber/v2/middleware/recover"
+	"github.com/mehmetymw/debezium-postgres-es/application/event"
 	"github.com/mehmetymw/debezium-postgres-es/application/service"
 	"github.com/mehmetymw/debezium-postgres-es/config"
+	"github.com/mehmetymw/debezium-postgres-es/domain/entity"
 	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/migrations"
 	"github.com/mehmetymw/debezium-postgres-es/infrastructure/persistence/repository"
+	"github.com/mehmetymw/debezium-postgres-es/infrastructure/search"
 	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/handlers"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/openapi"
 	"github.com/mehmetymw/debezium-postgres-es/interfaces/api/routes"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/graphql"
+	"github.com/mehmetymw/debezium-postgres-es/interfaces/rpc"
 )
 
 func main() {
@@ -27,36 +41,218 @@ func main() {
 	if err := config.ConnectDB(&cfg.PostgreSQL); err != nil {
 		log.Fatalf("Failed to connect to database: %v", err)
 	}
+	if err := repository.RegisterErrorTranslation(config.DB); err != nil {
+		log.Fatalf("Failed to configure database: %v", err)
+	}
+
+	// Connect to Elasticsearch
+	if err := config.ConnectES(&cfg.Elasticsearch); err != nil {
+		log.Fatalf("Failed to connect to Elasticsearch: %v", err)
+	}
 
 	// Run migrations
 	if err := migrations.RunMigrations(config.DB); err != nil {
 		log.Fatalf("Failed to run migrations: %v", err)
 	}
+	if err := migrations.EnsurePublication(config.DB, cfg.Pipeline.Publication, cfg.Pipeline.PublicationTables, cfg.Pipeline.ReplicaIdentity); err != nil {
+		log.Fatalf("Failed to configure publication: %v", err)
+	}
+	if err := migrations.EnsurePublication(config.DB, cfg.Pipeline.OutboxPublication, []string{"public.outbox"}, cfg.Pipeline.ReplicaIdentity); err != nil {
+		log.Fatalf("Failed to configure outbox publication: %v", err)
+	}
 
 	// Initialize repositories
 	orderRepo := repository.NewGormOrderRepository(config.DB)
+	customerRepo := repository.NewGormCustomerRepository(config.DB)
+	outboxRepo := repository.NewGormOutboxRepository(config.DB)
+	lockRepo := repository.NewPostgresLockRepository(config.DB)
+	transactor := repository.NewGormTransactor(config.DB)
+	idempotencyRepo := repository.NewGormIdempotencyRepository(config.DB)
+	signalRepo := repository.NewGormSignalRepository(config.DB)
+	heartbeatRepo := repository.NewGormHeartbeatRepository(config.DB)
+	heartbeatSearchRepo := search.NewESHeartbeatRepository(config.ES, cfg.Heartbeat.Index)
+	orderSearchRepo := search.NewESOrderRepository(config.ES, cfg.Elasticsearch.OrderIndex)
+	reconciliationRepo := repository.NewGormReconciliationRepository(config.DB)
+	replicationRepo := repository.NewGormReplicationRepository(config.DB)
+	schemaRepo := repository.NewGormSchemaRepository(config.DB)
+	mappingRepo := search.NewESMappingRepository(config.ES)
 
 	// Initialize services
-	orderService := service.NewOrderService(orderRepo)
+	dispatcher := event.NewDispatcher()
+	feed := event.NewFeed(outboxRepo, dispatcher, cfg.Stream.History)
+	orderService := service.NewOrderService(orderRepo, orderSearchRepo, customerRepo, transactor, dispatcher, cfg.Orders.AllowClientIDs)
+	customerService := service.NewCustomerService(customerRepo, orderRepo, transactor, dispatcher)
+	outboxService := service.NewOutboxService(outboxRepo, cfg.Pipeline.OutboxRetention)
+	idempotencyService := service.NewIdempotencyService(idempotencyRepo, cfg.Idempotency.TTL, cfg.Idempotency.Lease)
+	snapshotService := service.NewSnapshotService(signalRepo, lockRepo, cfg.Pipeline.PublicationTables,
+		cfg.Pipeline.SnapshotQuietPeriod, cfg.Pipeline.SnapshotStartTimeout, cfg.Pipeline.SignalRetention)
+
+	heartbeatID := cfg.Heartbeat.ID
+	if heartbeatID == "" {
+		heartbeatID, _ = os.Hostname()
+	}
+	heartbeatService := service.NewHeartbeatService(heartbeatRepo, heartbeatSearchRepo, heartbeatID,
+		cfg.Heartbeat.Timeout, cfg.Heartbeat.PollInterval, cfg.Heartbeat.SLO, cfg.Heartbeat.Samples)
+	reconcileService := service.NewReconcileService(orderRepo, orderSearchRepo, reconciliationRepo, snapshotService,
+		cfg.Reconcile.ChunkSize, cfg.Reconcile.MaxIDs)
+	replicationService := service.NewReplicationService(replicationRepo, cfg.Pipeline.SlotMaxRetainedWALMB*1024*1024, cfg.Pipeline.AllowSlotDrop)
+	schemaService := service.NewSchemaService(schemaRepo, mappingRepo)
+
+	if cfg.Auth.Secret == "" {
+		log.Printf("WARNING: auth.secret is not set; bearer tokens are rejected and every caller is anonymous")
+	}
+	authService := service.NewAuthService([]byte(cfg.Auth.Secret))
+
+	trackingSecret := []byte(cfg.Tracking.Secret)
+	if len(trackingSecret) == 0 {
+		log.Printf("WARNING: tracking.secret is not set; tracking tokens are only valid on this replica until it restarts")
+		trackingSecret = make([]byte, 32)
+		if _, err := rand.Read(trackingSecret); err != nil {
+			log.Fatalf("Failed to generate a tracking secret: %v", err)
+		}
+	}
+	trackingService := service.NewTrackingService(orderRepo, trackingSecret, cfg.Tracking.TokenTTL)
+
+	automationRules := toAutomationRules(cfg.Automation.Rules)
+	if err := service.ValidateAutomationRules(automationRules); err != nil {
+		log.Fatalf("Invalid automation configuration: %v", err)
+	}
+	automationService := service.NewAutomationService(orderRepo, orderService, lockRepo, automationRules, cfg.Automation.BatchSize)
+
+	// Check the migrated tables against the search mapping
+	indexedTables := []struct{ table, index string }{
+		{"public.orders", cfg.Elasticsearch.OrderIndex},
+		{"public.order_status_history", cfg.Elasticsearch.OrderHistoryIndex},
+	}
+	for _, indexed := range indexedTables {
+		if _, err := schemaService.Check(context.Background(), indexed.table, indexed.index); err != nil {
+			if errors.Is(err, service.ErrIncompatibleSchema) && cfg.Schema.FailOnIncompatible {
+				log.Fatalf("Refusing to start: %v", err)
+			}
+			log.Printf("WARNING: failed to check search mapping for %s: %v", indexed.table, err)
+		}
+	}
+
+	// Run a one-off command instead of the server when requested
+	if len(os.Args) > 1 && os.Args[1] == "reconcile" {
+		runReconcile(reconcileService, os.Args[2:])
+		return
+	}
+
+	// Start background workers
+	go snapshotService.Run(context.Background(), cfg.Pipeline.SnapshotPollInterval)
+	go heartbeatService.Run(context.Background(), cfg.Heartbeat.Interval)
+	go replicationService.Run(context.Background(), cfg.Pipeline.SlotCheckInterval)
+	go outboxService.Run(context.Background(), cfg.Pipeline.OutboxPruneInterval)
+	go feed.Run(context.Background(), cfg.Stream.PollInterval)
+	go idempotencyService.Run(context.Background(), cfg.Idempotency.PruneInterval)
+	if cfg.Orders.PurgeAfter > 0 {
+		go orderService.RunPurge(context.Background(), cfg.Orders.PurgeInterval, cfg.Orders.PurgeAfter)
+	}
+	if len(automationRules) > 0 && cfg.Automation.Interval > 0 {
+		go automationService.Run(context.Background(), cfg.Automation.Interval)
+	}
+	if cfg.Reconcile.Interval > 0 {
+		go reconcileService.RunSchedule(context.Background(), cfg.Reconcile.Interval, cfg.Reconcile.Repair)
+	}
 
 	// Initialize handlers
-	orderHandler := handlers.NewOrderHandler(orderService)
+	authHandler := handlers.NewAuthHandler(authService)
+	orderHandler := handlers.NewOrderHandler(orderService, idempotencyService, cfg.Orders.BulkAtomic, cfg.Orders.BulkMaxOperations)
+	orderStreamHandler := handlers.NewOrderStreamHandler(feed, cfg.Stream.Buffer, cfg.Stream.Heartbeat)
+	orderTrackingHandler := handlers.NewOrderTrackingHandler(trackingService, feed, cfg.Tracking.Buffer, cfg.Tracking.Heartbeat, cfg.Tracking.MaxSubscriptions)
+	customerHandler := handlers.NewCustomerHandler(customerService)
+	pipelineHandler := handlers.NewPipelineHandler(snapshotService)
+	graphqlHandler, err := graphql.NewHandler(orderService, customerService, cfg.GraphQL.MaxDepth)
+	if err != nil {
+		log.Fatalf("Failed to load GraphQL schema: %v", err)
+	}
+	adminHandler := handlers.NewAdminHandler(heartbeatService, reconcileService, replicationService, schemaService, automationService, cfg.Elasticsearch.OrderIndex)
+
+	// Load the API specification
+	spec, err := openapi.Load()
+	if err != nil {
+		log.Fatalf("Failed to load API specification: %v", err)
+	}
 
 	// Create Fiber app
 	app := fiber.New(fiber.Config{
-		AppName: "Debezium PostgreSQL to Elasticsearch",
+		AppName:      "Debezium PostgreSQL to Elasticsearch",
+		ErrorHandler: handlers.ErrorHandler,
 	})
 
 	// Middleware
 	app.Use(logger.New())
 	app.Use(recover.New())
 	app.Use(cors.New())
+	if cfg.OpenAPI.Validate {
+		app.Use(spec.Validate)
+	}
 
 	// Setup routes
-	routes.SetupRoutes(app, orderHandler)
+	routes.SetupRoutes(app, authHandler, orderHandler, orderStreamHandler, orderTrackingHandler, customerHandler, pipelineHandler, adminHandler, graphqlHandler, spec)
+
+	// Check the routes against the API specification
+	if drift := spec.Drift(app.GetRoutes(true)); len(drift) > 0 {
+		if cfg.OpenAPI.FailOnDrift {
+			log.Fatalf("Refusing to start: routes and API specification disagree: %s", strings.Join(drift, "; "))
+		}
+		for _, route := range drift {
+			log.Printf("WARNING: API specification drift: %s", route)
+		}
+	}
+
+	// Start the gRPC server next to the HTTP server
+	if cfg.GRPC.Port != "" {
+		listener, err := net.Listen("tcp", ":"+cfg.GRPC.Port)
+		if err != nil {
+			log.Fatalf("Failed to listen for gRPC: %v", err)
+		}
+		grpcServer := rpc.NewServer(rpc.NewOrderServer(orderService, feed, cfg.GRPC.WatchBuffer), authService)
+		go func() {
+			fmt.Printf("gRPC server is running on port %s\n", cfg.GRPC.Port)
+			if err := grpcServer.Serve(listener); err != nil {
+				log.Fatalf("gRPC server failed: %v", err)
+			}
+		}()
+	}
 
 	// Start server
 	port := cfg.Server.Port
 	fmt.Printf("Server is running on port %s\n", port)
 	log.Fatal(app.Listen(":" + port))
 }
+
+// runReconcile runs a single reconciliation and prints its report
+func runReconcile(reconcileService *service.ReconcileService, args []string) {
+	flags := flag.NewFlagSet("reconcile", flag.ExitOnError)
+	repair := flags.Bool("repair", false, "repair drift by reindexing missing and stale orders and deleting orphans")
+	flags.Parse(args)
+
+	report, err := reconcileService.Reconcile(context.Background(), *repair)
+	if err != nil {
+		log.Fatalf("Failed to run reconciliation: %v", err)
+	}
+
+	output, _ := json.MarshalIndent(report, "", "  ")
+	fmt.Println(string(output))
+	if report.Error != "" {
+		os.Exit(1)
+	}
+}
+
+// toAutomationRules converts the configured automation rules to domain rules
+func toAutomationRules(rules []config.AutomationRuleConfig) []entity.AutomationRule {
+	automationRules := make([]entity.AutomationRule, len(rules))
+	for i, rule := range rules {
+		automationRules[i] = entity.AutomationRule{
+			Name:         rule.Name,
+			Status:       rule.Status,
+			After:        rule.After,
+			Action:       rule.Action,
+			TargetStatus: rule.TargetStatus,
+			Reason:       rule.Reason,
+		}
+	}
+	return automationRules
+}